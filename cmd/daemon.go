@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/infrastructure/cmd/handlers"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl/myterm"
+	"varmijo/time-tracker/tt/infrastructure/daemon"
+	"varmijo/time-tracker/tt/infrastructure/display"
+	"varmijo/time-tracker/tt/infrastructure/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const socketFile = "tt.sock"
+
+// Runs the daemon owning the db and the system tray until it is quit from the
+// tray or interrupted, REPLs started meanwhile connect to it
+func runDaemon(kern *app.App, headless bool) error {
+	newHandler := func(session *app.App) repl.Handler {
+		return handlers.NewHandlers(session)
+	}
+
+	server, err := daemon.Listen(utils.GeAppPath(socketFile), kern, newHandler)
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+
+	go func() {
+		err := server.Serve()
+		if err != nil {
+			logrus.Errorf("Daemon stopped serving: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Daemon listening on %s\n", utils.GeAppPath(socketFile))
+
+	if headless {
+		<-ctx.Done()
+		return nil
+	}
+
+	gui := display.NewGUI(kern)
+
+	go func() {
+		<-ctx.Done()
+		gui.Done()
+	}()
+
+	gui.Run()
+
+	return nil
+}
+
+// Runs a REPL over the daemon connection
+func runClient(client *daemon.Client) {
+	term, closeTerm := myterm.NewTerm()
+	defer closeTerm()
+
+	term.PrintTitle("Welcome to Time Tracker CLI tool (daemon)")
+
+	repl.NewRepl(client, client, term, "exit").Run()
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/domain"
 	"varmijo/time-tracker/tt/infrastructure/cmd/handlers"
+	"varmijo/time-tracker/tt/infrastructure/cmd/oneshot"
 	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
 	"varmijo/time-tracker/tt/infrastructure/cmd/repl/myterm"
 	"varmijo/time-tracker/tt/infrastructure/config"
+	"varmijo/time-tracker/tt/infrastructure/daemon"
 	"varmijo/time-tracker/tt/infrastructure/display"
 	"varmijo/time-tracker/tt/infrastructure/repositories"
 	"varmijo/time-tracker/tt/infrastructure/utils"
@@ -18,28 +23,92 @@ import (
 const logFile = "tt.log"
 
 func main() {
-	cfg := config.MustNewConfig()
+	args, headless, err := parseGlobalOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	moved, err := utils.PrepareAppDirs([]string{"tt.db", "tt.db.*.bak", logFile, tokenFile}, []string{config.ConfigFileName})
+	for _, file := range moved {
+		fmt.Fprintf(os.Stderr, "Moved %s from the executable folder\n", file)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	clock := domain.SystemClock{}
+
+	cfg, err := config.NewConfig(clock)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	file := setLogger(cfg.GetLogLevel())
 	defer file.Close()
 
+	// The REPL connects to the daemon when there is one running
+	if len(args) == 0 {
+		if client, err := daemon.Dial(utils.GeAppPath(socketFile)); err == nil {
+			defer client.Close()
+			runClient(client)
+			return
+		}
+	}
+
 	// Create sqlite DB
 	db, err := repositories.NewSQLiteDB("tt")
 	if err != nil {
 		logrus.Fatalf("Failed to create SQLite DB: %v", err)
 	}
+	defer repositories.CloseSQLiteDB(db)
 
 	records := repositories.NewSQLiteRecordRepository(db)
 
 	track := repositories.NewSQLiteTrackRepository(db)
 
-	stats := repositories.NewSQLiteStatsRepository(db)
+	stats := repositories.NewSQLiteStatsRepository(db, clock)
 
-	app := app.NewApp(cfg, records, track, stats)
+	pool := repositories.NewSQLitePoolRepository(db)
 
-	gui := display.NewGUI(app)
+	templates := repositories.NewSQLiteTemplateRepository(db)
+
+	daysOff := repositories.NewSQLiteDayOffRepository(db)
+
+	tx := repositories.NewSQLiteTransactor(db)
+
+	app := app.NewApp(cfg, records, track, stats, pool, templates, daysOff, tx, clock)
+
+	if len(args) > 0 && args[0] == "serve" {
+		err := runServe(app, args[1:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if len(args) > 0 && args[0] == "daemon" {
+		err := runDaemon(app, headless)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
 
 	mux := handlers.NewHandlers(app)
 
+	// Any other arguments run a single command and exit
+	if len(args) > 0 {
+		os.Exit(oneshot.Run(app, mux, args, os.Stdout, os.Stderr, os.Stdin))
+	}
+
 	term, closeTerm := myterm.NewTerm()
 	defer closeTerm()
 
@@ -47,6 +116,15 @@ func main() {
 
 	cmds := repl.NewRepl(app.GetPromptData(), mux, term, "exit")
 
+	// Without a desktop only the REPL runs, the process ends with it
+	if headless {
+		logrus.Info("Running headless, system tray disabled")
+		cmds.Run()
+		return
+	}
+
+	gui := display.NewGUI(app)
+
 	go func() {
 		cmds.Run()
 		gui.Done()
@@ -55,6 +133,42 @@ func main() {
 	gui.Run()
 }
 
+// Options given before the command, --headless and --data-dir. The data dir
+// can also be set with TT_HOME.
+func parseGlobalOptions(args []string) ([]string, bool, error) {
+	headless := os.Getenv("TT_HEADLESS") != "" || !display.Available()
+
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(args[0], "--"), "=")
+
+		switch name {
+		case "headless":
+			headless = true
+		case "data-dir":
+			if !hasValue {
+				if len(args) < 2 {
+					return nil, false, fmt.Errorf("--data-dir needs a folder")
+				}
+
+				args = args[1:]
+				value = args[0]
+			}
+
+			err := utils.SetAppDir(value)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid --data-dir, %w", err)
+			}
+		default:
+			// Options of the one-shot commands, like --json
+			return args, headless, nil
+		}
+
+		args = args[1:]
+	}
+
+	return args, headless, nil
+}
+
 // Set up the application logger
 func setLogger(slevel string) *os.File {
 	file, err := os.OpenFile(utils.GeAppPath(logFile), os.O_APPEND|os.O_CREATE|os.O_RDWR, 0666)
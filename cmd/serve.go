@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/infrastructure/api"
+	"varmijo/time-tracker/tt/infrastructure/utils"
+)
+
+const tokenFile = "api.token"
+
+// Serves the HTTP JSON API until interrupted, for example
+// tt serve -addr 127.0.0.1:7707 or tt serve -socket /tmp/tt.sock
+func runServe(kern *app.App, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:7707", "tcp address to listen on")
+	socket := fs.String("socket", "", "unix socket to listen on instead of tcp")
+	token := fs.String("token", os.Getenv("TT_API_TOKEN"), "api token, read from "+tokenFile+" or created there by default")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *token == "" {
+		*token, err = loadOrCreateToken(utils.GeAppPath(tokenFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	listener, err := listen(*addr, *socket)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           api.NewServer(kern, *token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving the tt api on %s\n", listener.Addr())
+
+	err = server.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func listen(addr, socket string) (net.Listener, error) {
+	if socket == "" {
+		return net.Listen("tcp", addr)
+	}
+
+	// A socket left by a previous run would make listen fail
+	if _, err := os.Stat(socket); err == nil {
+		os.Remove(socket)
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Chmod(socket, 0600)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// Reads the token from the file, creating a new one readable only by the
+// user if it doesn't exist
+func loadOrCreateToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	}
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("can't read token, %w", err)
+	}
+
+	token, err := api.NewToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(path, []byte(token+"\n"), 0600)
+	if err != nil {
+		return "", fmt.Errorf("can't save token, %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "New api token saved on %s\n", path)
+
+	return token, nil
+}
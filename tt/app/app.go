@@ -1,23 +1,81 @@
 package app
 
 import (
+	"sync"
+	"time"
+
 	"varmijo/time-tracker/tt/domain"
 )
 
 type App struct {
-	date    domain.DateState
-	config  domain.ConfigRepository
-	records domain.RecordRepository
-	track   domain.TrackRepository
-	stats   domain.StatsRepository
+	clock     domain.Clock
+	date      domain.DateState
+	config    domain.ConfigRepository
+	records   domain.RecordRepository
+	track     domain.TrackRepository
+	stats     domain.StatsRepository
+	pool      domain.PoolRepository
+	templates domain.TemplateRepository
+	daysOff   domain.DayOffRepository
+	tx        domain.Transactor
+	prompt    *promptData
+	mu        *sync.Mutex
 }
 
-func NewApp(config domain.ConfigRepository, records domain.RecordRepository, track domain.TrackRepository, stats domain.StatsRepository) *App {
-	return &App{
-		config:  config,
-		records: records,
-		track:   track,
-		stats:   stats,
-		date:    domain.NewDateInMemory(),
+func NewApp(config domain.ConfigRepository, records domain.RecordRepository, track domain.TrackRepository, stats domain.StatsRepository, pool domain.PoolRepository, templates domain.TemplateRepository, daysOff domain.DayOffRepository, tx domain.Transactor, clock domain.Clock) *App {
+	kern := &App{
+		clock:     clock,
+		config:    config,
+		records:   records,
+		track:     track,
+		stats:     stats,
+		pool:      pool,
+		templates: templates,
+		daysOff:   daysOff,
+		tx:        tx,
+		date:      domain.NewDateInMemory(clock),
+		mu:        &sync.Mutex{},
 	}
+
+	kern.prompt = &promptData{app: kern, autoRefresh: true}
+
+	return kern
+}
+
+// Copy of the app with its own current date and prompt data, so the clients
+// sharing an app don't change the date of each other. It shares the
+// repositories and the lock with the app.
+func (kern *App) Session() *App {
+	session := &App{
+		clock:     kern.clock,
+		config:    kern.config,
+		records:   kern.records,
+		track:     kern.track,
+		stats:     kern.stats,
+		pool:      kern.pool,
+		templates: kern.templates,
+		daysOff:   kern.daysOff,
+		tx:        kern.tx,
+		date:      domain.NewDateInMemory(kern.clock),
+		mu:        kern.mu,
+	}
+
+	session.prompt = &promptData{app: session}
+
+	return session
+}
+
+// Serializes the use cases run by the clients sharing the app, like the
+// system tray and the daemon connections
+func (kern *App) Lock() {
+	kern.mu.Lock()
+}
+
+func (kern *App) Unlock() {
+	kern.mu.Unlock()
+}
+
+// Current time on the app clock
+func (kern *App) Now() time.Time {
+	return kern.clock.Now()
 }
@@ -0,0 +1,375 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"varmijo/time-tracker/tt/domain"
+)
+
+// In memory repositories for the use case tests, entities are copied on the
+// way in and out so changes are only seen once saved, like with the database
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+type fakeConfig struct {
+	schedule domain.Schedule
+	holidays []time.Time
+}
+
+func (c *fakeConfig) GetLogLevel() string          { return "error" }
+func (c *fakeConfig) GetSchedule() domain.Schedule { return c.schedule }
+func (c *fakeConfig) GetHolidays() []time.Time     { return c.holidays }
+func (c *fakeConfig) GetAll() []domain.ConfigEntry { return nil }
+func (c *fakeConfig) Set(key, value string) (domain.ConfigEntry, error) {
+	return domain.ConfigEntry{}, fmt.Errorf("config is read only")
+}
+
+func copyRecord(r *domain.Record) *domain.Record {
+	return domain.Must(domain.RecreateRecord(r.ID(), r.Date(), r.End(), r.Status().String(), r.Info()))
+}
+
+type memRecords struct {
+	records map[string]*domain.Record
+}
+
+func newMemRecords() *memRecords {
+	return &memRecords{records: map[string]*domain.Record{}}
+}
+
+func (m *memRecords) Save(ctx context.Context, r *domain.Record) error {
+	m.records[r.ID()] = copyRecord(r)
+	return nil
+}
+
+func (m *memRecords) Delete(ctx context.Context, id string) error {
+	delete(m.records, id)
+	return nil
+}
+
+func (m *memRecords) Get(ctx context.Context, id string) (*domain.Record, error) {
+	r, ok := m.records[id]
+	if !ok {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return copyRecord(r), nil
+}
+
+func (m *memRecords) filter(keep func(*domain.Record) bool) []*domain.Record {
+	records := []*domain.Record{}
+	for _, r := range m.records {
+		if keep(r) {
+			records = append(records, copyRecord(r))
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Date().Before(records[j].Date())
+	})
+
+	return records
+}
+
+func (m *memRecords) GetAllByDate(ctx context.Context, date time.Time) ([]*domain.Record, error) {
+	return m.filter(func(r *domain.Record) bool {
+		return domain.IsSameDay(r.Date(), date)
+	}), nil
+}
+
+func (m *memRecords) GetAllByDateAndStatus(ctx context.Context, date time.Time, status domain.RecordStatus) ([]*domain.Record, error) {
+	return m.filter(func(r *domain.Record) bool {
+		return domain.IsSameDay(r.Date(), date) && r.Status() == status
+	}), nil
+}
+
+func (m *memRecords) GetAllByStatus(ctx context.Context, status domain.RecordStatus) ([]*domain.Record, error) {
+	return m.filter(func(r *domain.Record) bool {
+		return r.Status() == status
+	}), nil
+}
+
+func (m *memRecords) GetAllInRange(ctx context.Context, from, to time.Time) ([]*domain.Record, error) {
+	return m.filter(func(r *domain.Record) bool {
+		return inRange(r.Date(), from, to)
+	}), nil
+}
+
+func inRange(date, from, to time.Time) bool {
+	day := domain.StartOfDay(date)
+	return !day.Before(domain.StartOfDay(from)) && !day.After(domain.StartOfDay(to))
+}
+
+type memTrack struct {
+	open *domain.OpenRecord
+}
+
+func (m *memTrack) Save(ctx context.Context, t *domain.OpenRecord) error {
+	m.open = domain.RecreateOpenRecord(t.Date(), t.Info())
+	return nil
+}
+
+func (m *memTrack) Get(ctx context.Context) (*domain.OpenRecord, error) {
+	if m.open == nil {
+		return nil, fmt.Errorf("record not started")
+	}
+
+	return domain.RecreateOpenRecord(m.open.Date(), m.open.Info()), nil
+}
+
+func (m *memTrack) Delete(ctx context.Context) error {
+	m.open = nil
+	return nil
+}
+
+func (m *memTrack) IsWorking(ctx context.Context) bool {
+	return m.open != nil
+}
+
+// Stats computed on the fly from the records and the open record
+type debtArgs struct {
+	from, to time.Time
+	schedule domain.Schedule
+	holidays []time.Time
+}
+
+type memStats struct {
+	records  *memRecords
+	track    *memTrack
+	clock    domain.Clock
+	debt     *domain.Debt
+	debtArgs *debtArgs
+}
+
+func (m *memStats) sum(records []*domain.Record) float64 {
+	total := 0.0
+	for _, r := range records {
+		total += r.Hours()
+	}
+
+	return total
+}
+
+func (m *memStats) GetHoursByDate(ctx context.Context, date time.Time) (float64, error) {
+	records, _ := m.records.GetAllByDate(ctx, date)
+	return m.sum(records), nil
+}
+
+func (m *memStats) GetHoursByDateAndStatus(ctx context.Context, date time.Time, status domain.RecordStatus) (float64, error) {
+	records, _ := m.records.GetAllByDateAndStatus(ctx, date, status)
+	return m.sum(records), nil
+}
+
+func (m *memStats) GetHoursByStatus(ctx context.Context, status domain.RecordStatus) (float64, error) {
+	records, _ := m.records.GetAllByStatus(ctx, status)
+	return m.sum(records), nil
+}
+
+func (m *memStats) GetHoursSummary(ctx context.Context, from, to time.Time) ([]domain.HoursSummary, error) {
+	records, _ := m.records.GetAllInRange(ctx, from, to)
+
+	summaries := make([]domain.HoursSummary, len(records))
+	for i, r := range records {
+		summaries[i] = domain.HoursSummary{Date: domain.StartOfDay(r.Date()), Project: r.Info().Project(), Status: r.Status(), Hours: r.Hours()}
+	}
+
+	return summaries, nil
+}
+
+func (m *memStats) GetTrackedHours(ctx context.Context) (float64, error) {
+	if m.track.open == nil {
+		return 0, nil
+	}
+
+	return m.track.open.Hours(m.clock.Now()), nil
+}
+
+// Returns the canned debt, the arguments of the last call are kept to check
+// what the app asks for
+func (m *memStats) GetDebt(ctx context.Context, from, to time.Time, schedule domain.Schedule, holidays []time.Time) (*domain.Debt, error) {
+	m.debtArgs = &debtArgs{from: from, to: to, schedule: schedule, holidays: holidays}
+
+	if m.debt == nil {
+		return &domain.Debt{}, nil
+	}
+
+	return m.debt, nil
+}
+
+type memPool struct {
+	entries  []*domain.PoolEntry
+	failSave bool
+}
+
+func (m *memPool) Save(ctx context.Context, e *domain.PoolEntry) error {
+	if m.failSave {
+		return fmt.Errorf("pool is read only")
+	}
+
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+func (m *memPool) GetAll(ctx context.Context) ([]*domain.PoolEntry, error) {
+	return m.entries, nil
+}
+
+func (m *memPool) GetBalance(ctx context.Context) (float64, error) {
+	balance := 0.0
+	for _, e := range m.entries {
+		balance += e.Balance()
+	}
+
+	return balance, nil
+}
+
+// Transaction over the records and the pool, restored if the function fails
+type memTx struct {
+	records *memRecords
+	track   *memTrack
+	pool    *memPool
+}
+
+func (m *memTx) WithTx(ctx context.Context, f func(ctx context.Context) error) error {
+	records := make(map[string]*domain.Record, len(m.records.records))
+	for id, r := range m.records.records {
+		records[id] = r
+	}
+
+	open := m.track.open
+	entries := append([]*domain.PoolEntry{}, m.pool.entries...)
+
+	err := f(ctx)
+	if err != nil {
+		m.records.records = records
+		m.track.open = open
+		m.pool.entries = entries
+	}
+
+	return err
+}
+
+type memTemplates struct {
+	templates map[string]*domain.Template
+}
+
+func (m *memTemplates) Save(ctx context.Context, t *domain.Template) error {
+	m.templates[t.Name()] = t
+	return nil
+}
+
+func (m *memTemplates) Get(ctx context.Context, name string) (*domain.Template, error) {
+	t, ok := m.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("template %q not found", name)
+	}
+
+	return t, nil
+}
+
+func (m *memTemplates) GetAll(ctx context.Context) ([]*domain.Template, error) {
+	templates := []*domain.Template{}
+	for _, t := range m.templates {
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}
+
+type memDaysOff struct {
+	days map[string]*domain.DayOff
+}
+
+func (m *memDaysOff) Save(ctx context.Context, days []*domain.DayOff) error {
+	for _, d := range days {
+		m.days[d.Date().Format(time.DateOnly)] = d
+	}
+
+	return nil
+}
+
+func (m *memDaysOff) Delete(ctx context.Context, from, to time.Time) (int, error) {
+	deleted := 0
+	for key, d := range m.days {
+		if inRange(d.Date(), from, to) {
+			delete(m.days, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func (m *memDaysOff) GetAll(ctx context.Context) ([]*domain.DayOff, error) {
+	days := []*domain.DayOff{}
+	for _, d := range m.days {
+		days = append(days, d)
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date().Before(days[j].Date())
+	})
+
+	return days, nil
+}
+
+func (m *memDaysOff) GetAllInRange(ctx context.Context, from, to time.Time) ([]*domain.DayOff, error) {
+	all, _ := m.GetAll(ctx)
+
+	days := []*domain.DayOff{}
+	for _, d := range all {
+		if inRange(d.Date(), from, to) {
+			days = append(days, d)
+		}
+	}
+
+	return days, nil
+}
+
+// App with empty in memory repositories, a flat 8 hours schedule and the
+// clock set at now
+type testApp struct {
+	kern    *App
+	clock   *fakeClock
+	config  *fakeConfig
+	records *memRecords
+	track   *memTrack
+	stats   *memStats
+	pool    *memPool
+	daysOff *memDaysOff
+}
+
+func newTestApp(now time.Time) *testApp {
+	clock := &fakeClock{now: now}
+	config := &fakeConfig{schedule: domain.NewFlatSchedule(8)}
+	records := newMemRecords()
+	track := &memTrack{}
+	pool := &memPool{}
+	stats := &memStats{records: records, track: track, clock: clock}
+	templates := &memTemplates{templates: map[string]*domain.Template{}}
+	daysOff := &memDaysOff{days: map[string]*domain.DayOff{}}
+	tx := &memTx{records: records, track: track, pool: pool}
+
+	return &testApp{
+		kern:    NewApp(config, records, track, stats, pool, templates, daysOff, tx, clock),
+		clock:   clock,
+		config:  config,
+		records: records,
+		track:   track,
+		stats:   stats,
+		pool:    pool,
+		daysOff: daysOff,
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -9,26 +10,66 @@ import (
 )
 
 type promptData struct {
-	app        *App
-	wt, tt, dt float64
+	app                *App
+	wt, ct, pt, tt, dt float64
+	holidays           []time.Time
+	autoRefresh        bool
+	once               sync.Once
 	sync.RWMutex
 }
 
-var promptDataInstance *promptData
-var promptDataOnce sync.Once
-
 func (p *promptData) RefreshData() {
-	p.Lock()
-	defer p.Unlock()
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	err := p.refresh(ctx)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Reads the data, it's only kept if all of it can be read
+func (p *promptData) refresh(ctx context.Context) error {
 	date := p.app.date.Get()
 
-	p.wt = domain.Must(p.app.stats.GetHoursByDate(ctx, date))
-	p.tt = domain.Must(p.app.stats.GetTrackedHours(ctx))
-	p.dt = domain.Must(p.app.stats.GetDebt(ctx, p.app.config.GetWorkTime()))
+	wt, err := p.app.stats.GetHoursByDateAndStatus(ctx, date, domain.Pending)
+	if err != nil {
+		return fmt.Errorf("can't get pending hours, %w", err)
+	}
+
+	ct, err := p.app.stats.GetHoursByDateAndStatus(ctx, date, domain.Committed)
+	if err != nil {
+		return fmt.Errorf("can't get committed hours, %w", err)
+	}
+
+	pt, err := p.app.pool.GetBalance(ctx)
+	if err != nil {
+		return fmt.Errorf("can't get pool balance, %w", err)
+	}
+
+	tt, err := p.app.stats.GetTrackedHours(ctx)
+	if err != nil {
+		return fmt.Errorf("can't get tracked hours, %w", err)
+	}
+
+	holidays, err := p.app.holidays(ctx)
+	if err != nil {
+		return err
+	}
+
+	debt, err := p.app.stats.GetDebt(ctx, time.Time{}, time.Time{}, p.app.config.GetSchedule(), holidays)
+	if err != nil {
+		return fmt.Errorf("can't get debt, %w", err)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.wt, p.ct, p.pt, p.tt = wt, ct, pt, tt
+	p.holidays = holidays
+	p.dt = debt.Total().Debt()
+
+	return nil
 }
 
 func (p *promptData) Wt() float64 {
@@ -36,6 +77,16 @@ func (p *promptData) Wt() float64 {
 	defer p.RUnlock()
 	return p.wt
 }
+func (p *promptData) Ct() float64 {
+	p.RLock()
+	defer p.RUnlock()
+	return p.ct
+}
+func (p *promptData) Pt() float64 {
+	p.RLock()
+	defer p.RUnlock()
+	return p.pt
+}
 func (p *promptData) Tt() float64 {
 	p.RLock()
 	defer p.RUnlock()
@@ -56,6 +107,12 @@ func (p *promptData) IsToday() bool {
 	return p.app.date.IsToday()
 }
 
+func (p *promptData) IsNonWorkingDay() bool {
+	p.RLock()
+	defer p.RUnlock()
+	return domain.IsNonWorkingDay(p.app.date.Get(), p.app.config.GetSchedule(), p.holidays)
+}
+
 func (p *promptData) GetDate() time.Time {
 	return p.app.date.Get()
 }
@@ -66,16 +123,32 @@ func (p *promptData) keepRefreshing() {
 	}
 }
 
+// Prompt data of the app, loaded on the first call. Session data is only
+// refreshed on demand.
 func (kern *App) GetPromptData() domain.PromptData {
-	promptDataOnce.Do(func() {
-		promptDataInstance = &promptData{
-			app: kern,
-		}
+	kern.prompt.once.Do(func() {
+		kern.prompt.RefreshData()
+		kern.prompt.startRefreshing()
+	})
 
-		promptDataInstance.RefreshData()
+	return kern.prompt
+}
 
-		go promptDataInstance.keepRefreshing()
-	})
+// Prompt data of the app loaded now, unlike RefreshData a storage error is
+// returned
+func (kern *App) LoadPromptData(ctx context.Context) (domain.PromptData, error) {
+	err := kern.prompt.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kern.prompt.once.Do(kern.prompt.startRefreshing)
 
-	return promptDataInstance
+	return kern.prompt, nil
+}
+
+func (p *promptData) startRefreshing() {
+	if p.autoRefresh {
+		go p.keepRefreshing()
+	}
 }
@@ -2,20 +2,60 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"varmijo/time-tracker/tt/domain"
 )
 
+// Start for a record added without an interval, right after the last record
+// on the date, or ending at the current time if the date has no records
+func (kern *App) addedRecordStart(ctx context.Context, hours float64) (time.Time, error) {
+	date := kern.date.Get()
+
+	records, err := kern.records.GetAllByDate(ctx, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't get records, %w", err)
+	}
+
+	if len(records) > 0 {
+		start := records[0].End()
+		for _, record := range records[1:] {
+			if record.End().After(start) {
+				start = record.End()
+			}
+		}
+
+		return start, nil
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	start := date.Add(-time.Duration(hours * float64(time.Hour)))
+
+	if start.Before(dayStart) {
+		return dayStart, nil
+	}
+
+	return start, nil
+}
+
 // Add a new record
-func (kern *App) AddRecord(ctx context.Context, hours float64) error {
-	record, err := domain.NewCloseRecord(kern.date.Get(), hours)
+func (kern *App) AddRecord(ctx context.Context, hours float64, info domain.RecordInfo) error {
+	start, err := kern.addedRecordStart(ctx, hours)
+	if err != nil {
+		return err
+	}
+
+	record, err := domain.NewCloseRecord(start, hours, info)
 	if err != nil {
 		return fmt.Errorf("error creating new record, %w", err)
 	}
 
-	err = kern.records.Save(ctx, record)
+	err = kern.saveNewRecord(ctx, record)
 	if err != nil {
 		return fmt.Errorf("new record can't be inserted, %w", err)
 	}
@@ -23,12 +63,44 @@ func (kern *App) AddRecord(ctx context.Context, hours float64) error {
 	return nil
 }
 
-func (kern *App) startRecordWithDate(ctx context.Context, recTime time.Time) error {
-	if kern.track.IsWorking(ctx) {
-		return fmt.Errorf("record already started")
+// Saves a new record, records on non working days go straight to the pool
+// with their ledger entry in the same transaction
+func (kern *App) saveNewRecord(ctx context.Context, record *domain.Record) error {
+	holidays, err := kern.holidays(ctx)
+	if err != nil {
+		return err
 	}
 
+	if !domain.IsNonWorkingDay(record.Date(), kern.config.GetSchedule(), holidays) {
+		return kern.records.Save(ctx, record)
+	}
+
+	err = record.SendToPool()
+	if err != nil {
+		return err
+	}
+
+	return kern.tx.WithTx(ctx, func(ctx context.Context) error {
+		err := kern.records.Save(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		return kern.addPoolEntry(ctx, record.Date(), record.Hours(), domain.PoolIn)
+	})
+}
+
+func (kern *App) startRecordWithDate(ctx context.Context, recTime time.Time, info domain.RecordInfo) error {
 	record := domain.NewOpenRecord(recTime)
+	record.UpdateInfo(info)
+
+	return kern.startOpenRecord(ctx, record)
+}
+
+func (kern *App) startOpenRecord(ctx context.Context, record *domain.OpenRecord) error {
+	if kern.track.IsWorking(ctx) {
+		return domain.Invalidf("record already started")
+	}
 
 	err := kern.track.Save(ctx, record)
 	if err != nil {
@@ -39,25 +111,33 @@ func (kern *App) startRecordWithDate(ctx context.Context, recTime time.Time) err
 }
 
 // Add a new record
-func (kern *App) StartRecord(ctx context.Context) error {
+func (kern *App) StartRecord(ctx context.Context, info domain.RecordInfo) error {
 	if !kern.date.IsToday() {
-		return fmt.Errorf("wrong date, change back to today")
+		return domain.Invalidf("wrong date, change back to today")
 	}
 
-	recTime := time.Now()
+	recTime := kern.clock.Now()
 
-	return kern.startRecordWithDate(ctx, recTime)
+	return kern.startRecordWithDate(ctx, recTime, info)
 }
 
-func (kern *App) StartRecordAt(ctx context.Context, hour time.Time) error {
+func (kern *App) StartRecordAt(ctx context.Context, hour time.Time, info domain.RecordInfo) error {
 	recTime := domain.SetDate(hour, kern.date.Get())
 
-	return kern.startRecordWithDate(ctx, recTime)
+	return kern.startRecordWithDate(ctx, recTime, info)
 }
 
+// Saves the open record and deletes it in a transaction, so it can't be
+// saved twice
 func (kern *App) stopRecordWithDate(ctx context.Context, endTime time.Time) (float64, error) {
+	return kern.inTx(ctx, func(ctx context.Context) (float64, error) {
+		return kern.stopOpenRecord(ctx, endTime)
+	})
+}
+
+func (kern *App) stopOpenRecord(ctx context.Context, endTime time.Time) (float64, error) {
 	if !kern.track.IsWorking(ctx) {
-		return 0, fmt.Errorf("record not started")
+		return 0, domain.Invalidf("record not started")
 	}
 
 	openRecord, err := kern.track.Get(ctx)
@@ -65,6 +145,10 @@ func (kern *App) stopRecordWithDate(ctx context.Context, endTime time.Time) (flo
 		return 0, err
 	}
 
+	if !endTime.After(openRecord.Date()) {
+		return 0, domain.Invalidf("record must end after its start")
+	}
+
 	hours := 0.0
 
 	if !openRecord.IsEmpty(endTime) {
@@ -75,7 +159,7 @@ func (kern *App) stopRecordWithDate(ctx context.Context, endTime time.Time) (flo
 
 		hours = record.Hours()
 
-		err = kern.records.Save(ctx, record)
+		err = kern.saveNewRecord(ctx, record)
 		if err != nil {
 			return 0, fmt.Errorf("error inserting new record, %w", err)
 		}
@@ -90,7 +174,7 @@ func (kern *App) stopRecordWithDate(ctx context.Context, endTime time.Time) (flo
 }
 
 func (kern *App) StopRecord(ctx context.Context) (float64, error) {
-	endTime := time.Now()
+	endTime := kern.clock.Now()
 
 	return kern.stopRecordWithDate(ctx, endTime)
 }
@@ -109,7 +193,7 @@ func (kern *App) ChangeDate(ctx context.Context, date time.Time) error {
 
 func (kern *App) DropRecord(ctx context.Context) (float64, error) {
 	if !kern.track.IsWorking(ctx) {
-		return 0, fmt.Errorf("record not started")
+		return 0, domain.Invalidf("record not started")
 	}
 
 	openRecord, err := kern.track.Get(ctx)
@@ -117,7 +201,7 @@ func (kern *App) DropRecord(ctx context.Context) (float64, error) {
 		return 0, err
 	}
 
-	record, err := openRecord.CloseRecord(time.Now())
+	record, err := openRecord.CloseRecord(kern.clock.Now())
 	if err != nil {
 		return 0, fmt.Errorf("can't close record, %w", err)
 	}
@@ -129,3 +213,911 @@ func (kern *App) DropRecord(ctx context.Context) (float64, error) {
 
 	return record.Hours(), nil
 }
+
+// Commits the pending records on the current date up to the given amount of
+// hours, an amount of 0 commits all of them. Time above the daily working time
+// is sent to the pool.
+func (kern *App) Commit(ctx context.Context, amount float64) (float64, float64, error) {
+	var committedHours, poolHours float64
+
+	err := kern.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		committedHours, poolHours, err = kern.commit(ctx, amount)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return committedHours, poolHours, nil
+}
+
+func (kern *App) commit(ctx context.Context, amount float64) (float64, float64, error) {
+	date := kern.date.Get()
+
+	pending, err := kern.records.GetAllByDateAndStatus(ctx, date, domain.Pending)
+	if err != nil {
+		return 0, 0, fmt.Errorf("can't get pending records, %w", err)
+	}
+
+	if len(pending) == 0 {
+		return 0, 0, domain.Invalidf("no pending records to commit")
+	}
+
+	committed, err := kern.stats.GetHoursByDateAndStatus(ctx, date, domain.Committed)
+	if err != nil {
+		return 0, 0, fmt.Errorf("can't get committed hours, %w", err)
+	}
+
+	holidays, err := kern.holidays(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	available := max(kern.scheduledHours(date, holidays)-committed, 0)
+
+	if amount <= 0 {
+		for _, record := range pending {
+			amount += record.Hours()
+		}
+	}
+
+	committedHours, poolHours := 0.0, 0.0
+
+	for _, record := range pending {
+		if amount < domain.MinHours {
+			break
+		}
+
+		rest, err := record.Split(amount)
+		if err != nil {
+			return 0, 0, fmt.Errorf("can't split record, %w", err)
+		}
+
+		if rest != nil {
+			err = kern.records.Save(ctx, rest)
+			if err != nil {
+				return 0, 0, fmt.Errorf("error saving record, %w", err)
+			}
+		}
+
+		amount -= record.Hours()
+
+		var overflow *domain.Record
+
+		if available >= domain.MinHours {
+			overflow, err = record.Split(available)
+			if err != nil {
+				return 0, 0, fmt.Errorf("can't split record, %w", err)
+			}
+
+			err = record.Commit()
+			if err != nil {
+				return 0, 0, err
+			}
+
+			available -= record.Hours()
+			committedHours += record.Hours()
+		} else {
+			overflow = record
+		}
+
+		if overflow != nil {
+			err = overflow.SendToPool()
+			if err != nil {
+				return 0, 0, err
+			}
+
+			err = kern.addPoolEntry(ctx, overflow.Date(), overflow.Hours(), domain.PoolIn)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			poolHours += overflow.Hours()
+
+			if overflow != record {
+				err = kern.records.Save(ctx, overflow)
+				if err != nil {
+					return 0, 0, fmt.Errorf("error saving record, %w", err)
+				}
+			}
+		}
+
+		err = kern.records.Save(ctx, record)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error saving record, %w", err)
+		}
+	}
+
+	return committedHours, poolHours, nil
+}
+
+// Runs a use case returning hours in a transaction
+func (kern *App) inTx(ctx context.Context, f func(ctx context.Context) (float64, error)) (float64, error) {
+	hours := 0.0
+
+	err := kern.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		hours, err = f(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return hours, nil
+}
+
+func (kern *App) addPoolEntry(ctx context.Context, date time.Time, hours float64, movement domain.PoolMovement) error {
+	entry, err := domain.NewPoolEntry(date, hours, movement)
+	if err != nil {
+		return fmt.Errorf("error creating pool entry, %w", err)
+	}
+
+	err = kern.pool.Save(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("error saving pool entry, %w", err)
+	}
+
+	return nil
+}
+
+// Sends all the pending records on the current date to the pool
+func (kern *App) SendToPool(ctx context.Context) (float64, error) {
+	return kern.inTx(ctx, kern.sendToPool)
+}
+
+func (kern *App) sendToPool(ctx context.Context) (float64, error) {
+	date := kern.date.Get()
+
+	pending, err := kern.records.GetAllByDateAndStatus(ctx, date, domain.Pending)
+	if err != nil {
+		return 0, fmt.Errorf("can't get pending records, %w", err)
+	}
+
+	if len(pending) == 0 {
+		return 0, domain.Invalidf("no pending records to send")
+	}
+
+	hours := 0.0
+
+	for _, record := range pending {
+		err = record.SendToPool()
+		if err != nil {
+			return 0, err
+		}
+
+		err = kern.records.Save(ctx, record)
+		if err != nil {
+			return 0, fmt.Errorf("error saving record, %w", err)
+		}
+
+		hours += record.Hours()
+	}
+
+	err = kern.addPoolEntry(ctx, date, hours, domain.PoolIn)
+	if err != nil {
+		return 0, err
+	}
+
+	return hours, nil
+}
+
+// Pours time from the pool to the current date as a committed record. An
+// amount of 0 pours what the date is missing to reach the working time.
+func (kern *App) PourPool(ctx context.Context, amount float64) (float64, error) {
+	return kern.inTx(ctx, func(ctx context.Context) (float64, error) {
+		return kern.pourPool(ctx, amount)
+	})
+}
+
+func (kern *App) pourPool(ctx context.Context, amount float64) (float64, error) {
+	date := kern.date.Get()
+
+	balance, err := kern.pool.GetBalance(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("can't get pool balance, %w", err)
+	}
+
+	if balance < domain.MinHours {
+		return 0, domain.Invalidf("pool is empty")
+	}
+
+	if amount <= 0 {
+		committed, err := kern.stats.GetHoursByDateAndStatus(ctx, date, domain.Committed)
+		if err != nil {
+			return 0, fmt.Errorf("can't get committed hours, %w", err)
+		}
+
+		holidays, err := kern.holidays(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		amount = min(kern.scheduledHours(date, holidays)-committed, balance)
+		if amount < domain.MinHours {
+			return 0, domain.Invalidf("current date has no missing time")
+		}
+	}
+
+	if amount > balance+domain.MinHours/2 {
+		return 0, domain.Invalidf("not enough time on the pool, %s available", domain.FormatDuration(balance))
+	}
+
+	amount = min(amount, balance)
+
+	start, err := kern.addedRecordStart(ctx, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	record, err := domain.NewCloseRecord(start, amount, domain.NewRecordInfo("Poured from pool", "", nil))
+	if err != nil {
+		return 0, fmt.Errorf("error creating new record, %w", err)
+	}
+
+	err = record.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	err = kern.records.Save(ctx, record)
+	if err != nil {
+		return 0, fmt.Errorf("new record can't be inserted, %w", err)
+	}
+
+	err = kern.addPoolEntry(ctx, date, record.Hours(), domain.PoolOut)
+	if err != nil {
+		return 0, err
+	}
+
+	return record.Hours(), nil
+}
+
+// Lists the records on the current date
+func (kern *App) ListRecords(ctx context.Context) ([]*domain.Record, error) {
+	records, err := kern.records.GetAllByDate(ctx, kern.date.Get())
+	if err != nil {
+		return nil, fmt.Errorf("can't get records, %w", err)
+	}
+
+	return records, nil
+}
+
+// Lists the records between two dates, both included
+func (kern *App) ListRecordsInRange(ctx context.Context, from, to time.Time) ([]*domain.Record, error) {
+	if domain.StartOfDay(to).Before(domain.StartOfDay(from)) {
+		return nil, domain.Invalidf("range start must be before its end")
+	}
+
+	records, err := kern.records.GetAllInRange(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("can't get records, %w", err)
+	}
+
+	return records, nil
+}
+
+// Finds a record on the current date by its position on the list or by a prefix of its id
+func (kern *App) findRecord(ctx context.Context, selector string) (*domain.Record, error) {
+	records, err := kern.ListRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if i, err := strconv.Atoi(selector); err == nil {
+		if i < 1 || i > len(records) {
+			return nil, domain.Invalidf("record %d not found on the list", i)
+		}
+
+		return records[i-1], nil
+	}
+
+	var found *domain.Record
+
+	for _, record := range records {
+		if selector == "" || !strings.HasPrefix(record.ID(), selector) {
+			continue
+		}
+
+		if found != nil {
+			return nil, domain.Invalidf("more than one record starts with %q", selector)
+		}
+
+		found = record
+	}
+
+	if found == nil {
+		return nil, domain.Invalidf("record %q not found on current date", selector)
+	}
+
+	return found, nil
+}
+
+func checkCanModify(record *domain.Record, force bool) error {
+	if !record.IsPending() && !force {
+		return domain.Invalidf("record is %s, use force to modify it", record.Status())
+	}
+
+	return nil
+}
+
+// Changes to apply to the info of a record, nil fields are kept
+type InfoChanges struct {
+	Description *string
+	Project     *string
+	Tags        *[]string
+}
+
+func (c InfoChanges) apply(info domain.RecordInfo) domain.RecordInfo {
+	description, project, tags := info.Description(), info.Project(), info.Tags()
+
+	if c.Description != nil {
+		description = *c.Description
+	}
+
+	if c.Project != nil {
+		project = *c.Project
+	}
+
+	if c.Tags != nil {
+		tags = *c.Tags
+	}
+
+	return domain.NewRecordInfo(description, project, tags)
+}
+
+// Changes to apply to a stored record, nil fields are kept
+type RecordChanges struct {
+	InfoChanges
+	Hours *float64
+	Start *time.Time
+}
+
+// Edits a record of the current date, committed and pool records are only
+// modified when forced. The pool ledger is updated in the same transaction.
+func (kern *App) EditStoredRecord(ctx context.Context, selector string, changes RecordChanges, force bool) (*domain.Record, error) {
+	var record *domain.Record
+
+	err := kern.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		record, err = kern.editStoredRecord(ctx, selector, changes, force)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (kern *App) editStoredRecord(ctx context.Context, selector string, changes RecordChanges, force bool) (*domain.Record, error) {
+	record, err := kern.findRecord(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkCanModify(record, force)
+	if err != nil {
+		return nil, err
+	}
+
+	oldHours := record.Hours()
+
+	if changes.Hours != nil {
+		err = record.UpdateHours(*changes.Hours)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if changes.Start != nil {
+		record.UpdateDate(domain.SetDate(*changes.Start, record.Date()))
+	}
+
+	record.UpdateInfo(changes.apply(record.Info()))
+
+	err = kern.records.Save(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("error saving record, %w", err)
+	}
+
+	if record.IsPool() {
+		diff := record.Hours() - oldHours
+
+		if diff >= domain.MinHours {
+			err = kern.addPoolEntry(ctx, record.Date(), diff, domain.PoolIn)
+		} else if diff <= -domain.MinHours {
+			err = kern.addPoolEntry(ctx, record.Date(), -diff, domain.PoolOut)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// Deletes a record of the current date, committed and pool records are only
+// deleted when forced. The pool ledger is updated in the same transaction.
+func (kern *App) DeleteStoredRecord(ctx context.Context, selector string, force bool) (*domain.Record, error) {
+	var record *domain.Record
+
+	err := kern.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		record, err = kern.deleteStoredRecord(ctx, selector, force)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (kern *App) deleteStoredRecord(ctx context.Context, selector string, force bool) (*domain.Record, error) {
+	record, err := kern.findRecord(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkCanModify(record, force)
+	if err != nil {
+		return nil, err
+	}
+
+	err = kern.records.Delete(ctx, record.ID())
+	if err != nil {
+		return nil, fmt.Errorf("error deleting record, %w", err)
+	}
+
+	if record.IsPool() {
+		err = kern.addPoolEntry(ctx, record.Date(), record.Hours(), domain.PoolOut)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// Gets the running open record
+func (kern *App) ViewRecord(ctx context.Context) (*domain.OpenRecord, error) {
+	if !kern.track.IsWorking(ctx) {
+		return nil, domain.Invalidf("record not started")
+	}
+
+	return kern.track.Get(ctx)
+}
+
+// Changes to apply to the open record, nil fields are kept
+type OpenRecordChanges struct {
+	InfoChanges
+	Start *time.Time
+}
+
+// Edits the running open record, keeping it running
+func (kern *App) EditRecord(ctx context.Context, changes OpenRecordChanges) (*domain.OpenRecord, error) {
+	openRecord, err := kern.ViewRecord(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.Start != nil {
+		err = openRecord.UpdateStart(domain.SetDate(*changes.Start, openRecord.Date()), kern.clock.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	openRecord.UpdateInfo(changes.apply(openRecord.Info()))
+
+	err = kern.track.Save(ctx, openRecord)
+	if err != nil {
+		return nil, fmt.Errorf("error saving open record, %w", err)
+	}
+
+	return openRecord, nil
+}
+
+// Adds or replaces a record template
+func (kern *App) AddTemplate(ctx context.Context, name, description, project string, hours float64) error {
+	template, err := domain.NewTemplate(name, description, project, hours)
+	if err != nil {
+		return fmt.Errorf("error creating template, %w", err)
+	}
+
+	err = kern.templates.Save(ctx, template)
+	if err != nil {
+		return fmt.Errorf("template can't be saved, %w", err)
+	}
+
+	return nil
+}
+
+func (kern *App) ListTemplates(ctx context.Context) ([]*domain.Template, error) {
+	templates, err := kern.templates.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get templates, %w", err)
+	}
+
+	return templates, nil
+}
+
+// Adds a record from a template, 0 hours uses the template duration
+func (kern *App) AddRecordFromTemplate(ctx context.Context, name string, hours float64) (float64, error) {
+	template, err := kern.templates.Get(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	if hours <= 0 {
+		if !template.HasHours() {
+			return 0, domain.Invalidf("template %s has no duration, hours are required", template.Name())
+		}
+
+		hours = template.Hours()
+	}
+
+	err = kern.AddRecord(ctx, hours, template.Info())
+	if err != nil {
+		return 0, err
+	}
+
+	return hours, nil
+}
+
+// Starts a new record now using the template info
+func (kern *App) StartRecordFromTemplate(ctx context.Context, name string) error {
+	if !kern.date.IsToday() {
+		return domain.Invalidf("wrong date, change back to today")
+	}
+
+	template, err := kern.templates.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	record := domain.NewOpenRecord(kern.clock.Now())
+	record.UpdateInfo(template.Info())
+
+	return kern.startOpenRecord(ctx, record)
+}
+
+// Holidays on the config and days off on the calendar
+func (kern *App) holidays(ctx context.Context) ([]time.Time, error) {
+	daysOff, err := kern.daysOff.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get days off, %w", err)
+	}
+
+	holidays := kern.config.GetHolidays()
+	for _, day := range daysOff {
+		holidays = append(holidays, day.Date())
+	}
+
+	return holidays, nil
+}
+
+// Hours to work on a date by the schedule, none on non working days
+func (kern *App) scheduledHours(date time.Time, holidays []time.Time) float64 {
+	schedule := kern.config.GetSchedule()
+
+	if domain.IsNonWorkingDay(date, schedule, holidays) {
+		return 0
+	}
+
+	return schedule.HoursOn(date)
+}
+
+// Builds a report of the recorded time between two dates, both included
+func (kern *App) Report(ctx context.Context, from, to time.Time) (*domain.Report, error) {
+	if domain.StartOfDay(to).Before(domain.StartOfDay(from)) {
+		return nil, domain.Invalidf("report start must be before its end")
+	}
+
+	summaries, err := kern.stats.GetHoursSummary(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("can't get hours summary, %w", err)
+	}
+
+	holidays, err := kern.holidays(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing is expected in the future
+	expectedHours := func(date time.Time) float64 {
+		if date.After(kern.clock.Now()) {
+			return 0
+		}
+
+		return kern.scheduledHours(date, holidays)
+	}
+
+	return domain.NewReport(from, to, kern.clock.Now(), summaries, expectedHours), nil
+}
+
+// Balance of the days between two dates, both included. A zero from starts
+// at the first record and a zero to ends today.
+func (kern *App) Debt(ctx context.Context, from, to time.Time) (*domain.Debt, error) {
+	if !from.IsZero() && !to.IsZero() && domain.StartOfDay(to).Before(domain.StartOfDay(from)) {
+		return nil, domain.Invalidf("debt start must be before its end")
+	}
+
+	holidays, err := kern.holidays(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	debt, err := kern.stats.GetDebt(ctx, from, to, kern.config.GetSchedule(), holidays)
+	if err != nil {
+		return nil, fmt.Errorf("can't get debt, %w", err)
+	}
+
+	return debt, nil
+}
+
+// Reports the week of the current date
+func (kern *App) WeekReport(ctx context.Context) (*domain.Report, error) {
+	from, to := domain.WeekRange(kern.date.Get())
+
+	return kern.Report(ctx, from, to)
+}
+
+// Reports the month of the given date
+func (kern *App) MonthReport(ctx context.Context, month time.Time) (*domain.Report, error) {
+	from, to := domain.MonthRange(month)
+
+	return kern.Report(ctx, from, to)
+}
+
+func (kern *App) GetDate() time.Time {
+	return kern.date.Get()
+}
+
+// Parses a date on the cli formats, relative dates count from the app clock
+func (kern *App) ParseDate(sdate string) (time.Time, error) {
+	return domain.GetDateFromText(sdate, kern.clock.Now())
+}
+
+// Result of an import, counts of the imported and skipped records
+type ImportSummary struct {
+	Imported       int
+	DuplicatedID   int
+	DuplicatedTime int
+	Hours          float64
+	PoolHours      float64
+	From, To       time.Time
+}
+
+func isDuplicatedTime(record *domain.Record, existing []*domain.Record) bool {
+	for _, other := range existing {
+		if other.Date().Equal(record.Date()) && other.Hours() == record.Hours() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Imports records skipping the ones with an existing id or with the same
+// start and duration as an existing record. Records on non working days are
+// sent to the pool with their ledger entry. A dry run only builds the summary,
+// otherwise the whole import is saved or nothing is.
+func (kern *App) ImportRecords(ctx context.Context, records []*domain.Record, dryRun bool) (ImportSummary, error) {
+	if dryRun {
+		return kern.importRecords(ctx, records, true)
+	}
+
+	summary := ImportSummary{}
+
+	err := kern.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		summary, err = kern.importRecords(ctx, records, false)
+		return err
+	})
+	if err != nil {
+		return ImportSummary{}, err
+	}
+
+	return summary, nil
+}
+
+func (kern *App) importRecords(ctx context.Context, records []*domain.Record, dryRun bool) (ImportSummary, error) {
+	summary := ImportSummary{}
+
+	holidays, err := kern.holidays(ctx)
+	if err != nil {
+		return summary, err
+	}
+
+	schedule := kern.config.GetSchedule()
+
+	seenIDs := map[string]bool{}
+	byDate := map[string][]*domain.Record{}
+
+	for _, record := range records {
+		_, err := kern.records.Get(ctx, record.ID())
+		if err == nil || seenIDs[record.ID()] {
+			summary.DuplicatedID++
+			continue
+		}
+
+		if !errors.Is(err, domain.ErrRecordNotFound) {
+			return summary, fmt.Errorf("can't check existing records, %w", err)
+		}
+
+		day := record.Date().Format(time.DateOnly)
+
+		existing, ok := byDate[day]
+		if !ok {
+			existing, err = kern.records.GetAllByDate(ctx, record.Date())
+			if err != nil {
+				return summary, fmt.Errorf("can't get records, %w", err)
+			}
+		}
+
+		if isDuplicatedTime(record, existing) {
+			summary.DuplicatedTime++
+			continue
+		}
+
+		byDate[day] = append(existing, record)
+		seenIDs[record.ID()] = true
+
+		// Time on non working days goes to the pool, like new records
+		if !record.IsPool() && domain.IsNonWorkingDay(record.Date(), schedule, holidays) {
+			record, err = domain.RecreateRecord(record.ID(), record.Date(), record.End(), domain.Pool.String(), record.Info())
+			if err != nil {
+				return summary, err
+			}
+		}
+
+		if !dryRun {
+			err = kern.records.Save(ctx, record)
+			if err != nil {
+				return summary, fmt.Errorf("error saving record, %w", err)
+			}
+
+			if record.IsPool() {
+				err = kern.addPoolEntry(ctx, record.Date(), record.Hours(), domain.PoolIn)
+				if err != nil {
+					return summary, err
+				}
+			}
+		}
+
+		if record.IsPool() {
+			summary.PoolHours += record.Hours()
+		}
+
+		if summary.Imported == 0 || record.Date().Before(summary.From) {
+			summary.From = record.Date()
+		}
+
+		if summary.Imported == 0 || record.Date().After(summary.To) {
+			summary.To = record.Date()
+		}
+
+		summary.Imported++
+		summary.Hours += record.Hours()
+	}
+
+	return summary, nil
+}
+
+func (kern *App) ListConfig() []domain.ConfigEntry {
+	return kern.config.GetAll()
+}
+
+// Changes a setting, the prompt data is refreshed as it may depend on it
+func (kern *App) SetConfig(ctx context.Context, key, value string) (domain.ConfigEntry, error) {
+	entry, err := kern.config.Set(key, value)
+	if err != nil {
+		return domain.ConfigEntry{}, err
+	}
+
+	kern.GetPromptData().RefreshData()
+
+	return entry, nil
+}
+
+// Marks the days between both dates as days off, returns the days saved
+func (kern *App) AddDaysOff(ctx context.Context, from, to time.Time, reason string) (int, error) {
+	from, to = domain.StartOfDay(from), domain.StartOfDay(to)
+	if to.Before(from) {
+		return 0, domain.Invalidf("days off start must be before their end")
+	}
+
+	days := []*domain.DayOff{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		days = append(days, domain.NewDayOff(date, reason))
+	}
+
+	err := kern.daysOff.Save(ctx, days)
+	if err != nil {
+		return 0, fmt.Errorf("can't save days off, %w", err)
+	}
+
+	return len(days), nil
+}
+
+// Saves days off read from a calendar, returns the days saved
+func (kern *App) ImportDaysOff(ctx context.Context, days []*domain.DayOff) (int, error) {
+	if len(days) == 0 {
+		return 0, domain.Invalidf("no days off to import")
+	}
+
+	err := kern.daysOff.Save(ctx, days)
+	if err != nil {
+		return 0, fmt.Errorf("can't save days off, %w", err)
+	}
+
+	return len(days), nil
+}
+
+func (kern *App) DeleteDaysOff(ctx context.Context, from, to time.Time) (int, error) {
+	deleted, err := kern.daysOff.Delete(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("can't delete days off, %w", err)
+	}
+
+	if deleted == 0 {
+		if len(kern.settingHolidays(from, to)) > 0 {
+			return 0, domain.Invalidf("no days off found, holidays of the setting are changed with config set; holidays")
+		}
+
+		return 0, domain.Invalidf("no days off found")
+	}
+
+	return deleted, nil
+}
+
+// Dates of the holidays setting within a range
+func (kern *App) settingHolidays(from, to time.Time) []time.Time {
+	holidays := []time.Time{}
+
+	for _, holiday := range kern.config.GetHolidays() {
+		day := domain.StartOfDay(holiday)
+		if !day.Before(domain.StartOfDay(from)) && !day.After(domain.StartOfDay(to)) {
+			holidays = append(holidays, day)
+		}
+	}
+
+	return holidays
+}
+
+// Reason of the days off that come from the holidays setting
+const HolidaysSettingReason = "holidays setting"
+
+// Lists the days off of a range, the dates of the holidays setting are listed
+// too with their own reason, as both are non working days
+func (kern *App) ListDaysOff(ctx context.Context, from, to time.Time) ([]*domain.DayOff, error) {
+	days, err := kern.daysOff.GetAllInRange(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("can't get days off, %w", err)
+	}
+
+	stored := map[string]bool{}
+	for _, day := range days {
+		stored[day.Date().Format(time.DateOnly)] = true
+	}
+
+	for _, holiday := range kern.settingHolidays(from, to) {
+		key := holiday.Format(time.DateOnly)
+		if stored[key] {
+			continue
+		}
+
+		stored[key] = true
+		days = append(days, domain.NewDayOff(holiday, HolidaysSettingReason))
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date().Before(days[j].Date())
+	})
+
+	return days, nil
+}
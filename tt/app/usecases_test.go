@@ -0,0 +1,895 @@
+package app
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"varmijo/time-tracker/tt/domain"
+)
+
+// Wednesday 2026-10-14 at the given time
+func at(hour, min int) time.Time {
+	return time.Date(2026, time.October, 14, hour, min, 0, 0, time.Local)
+}
+
+// Day of october 2026, the 12th is a monday
+func october(day int) time.Time {
+	return time.Date(2026, time.October, day, 0, 0, 0, 0, time.Local)
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < domain.MinHours/2
+}
+
+var noInfo = domain.NewRecordInfo("", "", nil)
+
+func (a *testApp) addRecord(t *testing.T, date time.Time, hours float64) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	err := a.kern.ChangeDate(ctx, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = a.kern.AddRecord(ctx, hours, noInfo)
+	if err != nil {
+		t.Fatalf("can't add record: %v", err)
+	}
+}
+
+func (a *testApp) hours(t *testing.T, date time.Time, status domain.RecordStatus) float64 {
+	t.Helper()
+
+	records, _ := a.records.GetAllByDateAndStatus(context.Background(), date, status)
+
+	total := 0.0
+	for _, r := range records {
+		total += r.Hours()
+	}
+
+	return total
+}
+
+func (a *testApp) poolBalance() float64 {
+	balance, _ := a.pool.GetBalance(context.Background())
+	return balance
+}
+
+func TestAddRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		date     time.Time
+		holidays []time.Time
+		daysOff  []time.Time
+		status   domain.RecordStatus
+		pool     float64
+	}{
+		{name: "working day", date: at(12, 0), status: domain.Pending},
+		{name: "weekend", date: october(17), status: domain.Pool, pool: 2},
+		{name: "holiday", date: october(13), holidays: []time.Time{october(13)}, status: domain.Pool, pool: 2},
+		{name: "day off", date: october(13), daysOff: []time.Time{october(13)}, status: domain.Pool, pool: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			a := newTestApp(at(12, 0))
+			a.config.holidays = tt.holidays
+
+			for _, day := range tt.daysOff {
+				_, err := a.kern.AddDaysOff(ctx, day, day, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			a.addRecord(t, tt.date, 2)
+
+			if got := a.hours(t, tt.date, tt.status); got != 2 {
+				t.Errorf("got %v %s hours, want 2", got, tt.status)
+			}
+
+			if got := a.poolBalance(); got != tt.pool {
+				t.Errorf("pool balance %v, want %v", got, tt.pool)
+			}
+		})
+	}
+}
+
+func TestAddRecordStart(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	a.addRecord(t, at(12, 0), 2)
+	a.addRecord(t, at(12, 0), 1)
+
+	records, err := a.kern.ListRecords(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	// The first one ends now and the next goes right after it
+	if !records[0].Date().Equal(at(10, 0)) || !records[1].Date().Equal(at(12, 0)) {
+		t.Errorf("records start at %v and %v", records[0].Date(), records[1].Date())
+	}
+}
+
+func TestStartAndStopRecord(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(9, 0))
+
+	_, err := a.kern.StopRecord(ctx)
+	if err == nil {
+		t.Error("stopping without a record started must fail")
+	}
+
+	err = a.kern.StartRecord(ctx, domain.NewRecordInfo("review", "acme", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = a.kern.StartRecord(ctx, noInfo)
+	if err == nil {
+		t.Error("starting a record twice must fail")
+	}
+
+	a.clock.advance(90 * time.Minute)
+
+	tracked, _ := a.kern.stats.GetTrackedHours(ctx)
+	if tracked != 1.5 {
+		t.Errorf("tracked %v hours, want 1.5", tracked)
+	}
+
+	hours, err := a.kern.StopRecord(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hours != 1.5 {
+		t.Errorf("stopped with %v hours, want 1.5", hours)
+	}
+
+	records, _ := a.kern.ListRecords(ctx)
+	if len(records) != 1 || !records[0].Date().Equal(at(9, 0)) || records[0].Info().Project() != "acme" {
+		t.Fatalf("unexpected records %v", records)
+	}
+
+	if a.track.open != nil {
+		t.Error("open record must be deleted")
+	}
+}
+
+func TestStartRecordOnOtherDate(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(9, 0))
+
+	err := a.kern.ChangeDate(ctx, october(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = a.kern.StartRecord(ctx, noInfo)
+	if err == nil {
+		t.Error("starting a record on another date must fail")
+	}
+
+	err = a.kern.StartRecordAt(ctx, at(9, 0), noInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hours, err := a.kern.StopRecordAt(ctx, at(11, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hours != 2.5 || a.hours(t, october(13), domain.Pending) != 2.5 {
+		t.Errorf("stopped with %v hours, want 2.5 on the date", hours)
+	}
+}
+
+func TestStopRecordBeforeItsStart(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	err := a.kern.StartRecordAt(ctx, at(10, 0), noInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, end := range []time.Time{at(9, 0), at(10, 0)} {
+		_, err = a.kern.StopRecordAt(ctx, end)
+		if !domain.IsInvalid(err) {
+			t.Errorf("stopping at %v got %v, want an invalid error", end.Format(time.Kitchen), err)
+		}
+	}
+
+	if a.track.open == nil || !a.track.open.Date().Equal(at(10, 0)) {
+		t.Error("open record must be kept")
+	}
+
+	if records, _ := a.kern.ListRecords(ctx); len(records) != 0 {
+		t.Errorf("got records %v, want none", records)
+	}
+}
+
+func TestStopRecordRollsBack(t *testing.T) {
+	ctx := context.Background()
+
+	// Saturday, the record goes to the pool
+	a := newTestApp(october(17).Add(12 * time.Hour))
+
+	err := a.kern.StartRecord(ctx, noInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.clock.advance(2 * time.Hour)
+	a.pool.failSave = true
+
+	_, err = a.kern.StopRecord(ctx)
+	if err == nil {
+		t.Fatal("stop must fail")
+	}
+
+	err = a.kern.AddRecord(ctx, 1, noInfo)
+	if err == nil {
+		t.Fatal("add must fail")
+	}
+
+	if got := a.hours(t, october(17), domain.Pool); got != 0 {
+		t.Errorf("%v pool hours without a ledger entry, want 0", got)
+	}
+
+	if a.track.open == nil {
+		t.Error("open record must be kept")
+	}
+}
+
+func TestDropRecord(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(9, 0))
+
+	err := a.kern.StartRecord(ctx, noInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.clock.advance(30 * time.Minute)
+
+	hours, err := a.kern.DropRecord(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hours != 0.5 {
+		t.Errorf("dropped %v hours, want 0.5", hours)
+	}
+
+	if a.track.open != nil || len(a.records.records) != 0 {
+		t.Error("dropped record must not be kept")
+	}
+}
+
+func TestEditRecord(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(9, 0))
+
+	err := a.kern.StartRecord(ctx, noInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	future := at(10, 0)
+	_, err = a.kern.EditRecord(ctx, OpenRecordChanges{Start: &future})
+	if err == nil {
+		t.Error("a record can't start in the future")
+	}
+
+	start, project := at(8, 15), "acme"
+	record, err := a.kern.EditRecord(ctx, OpenRecordChanges{Start: &start, InfoChanges: InfoChanges{Project: &project}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Hours(a.clock.Now()) != 0.75 || record.Info().Project() != "acme" {
+		t.Errorf("got %v hours on %q", record.Hours(a.clock.Now()), record.Info().Project())
+	}
+}
+
+func TestCommit(t *testing.T) {
+	tests := []struct {
+		name      string
+		committed float64
+		pending   []float64
+		amount    float64
+		wantErr   bool
+		commit    float64
+		pool      float64
+		left      float64
+	}{
+		{name: "no pending records", wantErr: true},
+		{name: "within the working time", pending: []float64{2, 4}, commit: 6},
+		{name: "over the working time", pending: []float64{6, 4}, commit: 8, pool: 2},
+		{name: "an amount", pending: []float64{6}, amount: 2, commit: 2, left: 4},
+		{name: "an amount over the working time", pending: []float64{6, 4}, amount: 9, commit: 8, pool: 1, left: 1},
+		{name: "day already committed", committed: 8, pending: []float64{1.5}, pool: 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			a := newTestApp(at(20, 0))
+
+			if tt.committed > 0 {
+				a.addRecord(t, at(20, 0), tt.committed)
+				_, _, err := a.kern.Commit(ctx, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, hours := range tt.pending {
+				a.addRecord(t, at(20, 0), hours)
+			}
+
+			commit, pool, err := a.kern.Commit(ctx, tt.amount)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("commit must fail")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !near(commit, tt.commit) || !near(pool, tt.pool) {
+				t.Errorf("committed %v and pooled %v, want %v and %v", commit, pool, tt.commit, tt.pool)
+			}
+
+			if got := a.hours(t, at(20, 0), domain.Pending); !near(got, tt.left) {
+				t.Errorf("%v pending hours left, want %v", got, tt.left)
+			}
+
+			if got := a.poolBalance(); !near(got, tt.pool) {
+				t.Errorf("pool balance %v, want %v", got, tt.pool)
+			}
+		})
+	}
+}
+
+func TestCommitRollsBack(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(20, 0))
+
+	a.addRecord(t, at(20, 0), 6)
+	a.addRecord(t, at(20, 0), 4)
+
+	// The overflow can't be saved on the pool after the first record is committed
+	a.pool.failSave = true
+
+	_, _, err := a.kern.Commit(ctx, 0)
+	if err == nil {
+		t.Fatal("commit must fail")
+	}
+
+	if got := a.hours(t, at(20, 0), domain.Pending); got != 10 {
+		t.Errorf("%v pending hours, want 10", got)
+	}
+
+	if got := a.hours(t, at(20, 0), domain.Committed); got != 0 {
+		t.Errorf("%v committed hours, want 0", got)
+	}
+}
+func TestSendToPool(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(20, 0))
+
+	_, err := a.kern.SendToPool(ctx)
+	if err == nil {
+		t.Error("sending without pending records must fail")
+	}
+
+	a.addRecord(t, at(20, 0), 1)
+	a.addRecord(t, at(20, 0), 2)
+
+	hours, err := a.kern.SendToPool(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hours != 3 || a.poolBalance() != 3 || a.hours(t, at(20, 0), domain.Pool) != 3 {
+		t.Errorf("sent %v hours, pool balance %v", hours, a.poolBalance())
+	}
+}
+
+func TestPoolRollsBack(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(20, 0))
+
+	// Saturday time is already on the pool
+	a.addRecord(t, october(17), 3)
+
+	err := a.kern.ChangeDate(ctx, at(20, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.addRecord(t, at(20, 0), 2)
+
+	// The ledger entry is written after the records
+	a.pool.failSave = true
+
+	_, err = a.kern.SendToPool(ctx)
+	if err == nil {
+		t.Fatal("send to pool must fail")
+	}
+
+	if got := a.hours(t, at(20, 0), domain.Pending); got != 2 {
+		t.Errorf("%v pending hours, want 2", got)
+	}
+
+	_, err = a.kern.PourPool(ctx, 1)
+	if err == nil {
+		t.Fatal("pour must fail")
+	}
+
+	if got := a.hours(t, at(20, 0), domain.Committed); got != 0 {
+		t.Errorf("%v committed hours, want 0", got)
+	}
+}
+
+func TestPourPool(t *testing.T) {
+	tests := []struct {
+		name      string
+		pool      float64
+		committed float64
+		amount    float64
+		wantErr   bool
+		poured    float64
+	}{
+		{name: "empty pool", amount: 1, wantErr: true},
+		{name: "missing time", pool: 3, committed: 6, poured: 2},
+		{name: "missing time over the balance", pool: 1, committed: 6, poured: 1},
+		{name: "nothing missing", pool: 3, committed: 8, wantErr: true},
+		{name: "an amount", pool: 3, amount: 2.5, poured: 2.5},
+		{name: "an amount over the balance", pool: 3, amount: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			a := newTestApp(at(20, 0))
+
+			if tt.pool > 0 {
+				a.addRecord(t, october(17), tt.pool)
+			}
+
+			if tt.committed > 0 {
+				a.addRecord(t, at(20, 0), tt.committed)
+				_, _, err := a.kern.Commit(ctx, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := a.kern.ChangeDate(ctx, at(20, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			poured, err := a.kern.PourPool(ctx, tt.amount)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("pour must fail")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if poured != tt.poured {
+				t.Errorf("poured %v, want %v", poured, tt.poured)
+			}
+
+			if got := a.hours(t, at(20, 0), domain.Committed); got != tt.committed+tt.poured {
+				t.Errorf("%v hours committed, want %v", got, tt.committed+tt.poured)
+			}
+
+			if got := a.poolBalance(); !near(got, tt.pool-tt.poured) {
+				t.Errorf("pool balance %v, want %v", got, tt.pool-tt.poured)
+			}
+		})
+	}
+}
+
+func TestEditAndDeleteStoredRecord(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(20, 0))
+
+	a.addRecord(t, at(20, 0), 2)
+	_, _, err := a.kern.Commit(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hours := 3.0
+	_, err = a.kern.EditStoredRecord(ctx, "1", RecordChanges{Hours: &hours}, false)
+	if err == nil {
+		t.Error("editing a committed record must need force")
+	}
+
+	record, err := a.kern.EditStoredRecord(ctx, "1", RecordChanges{Hours: &hours}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Hours() != 3 || a.hours(t, at(20, 0), domain.Committed) != 3 {
+		t.Errorf("edited record has %v hours, want 3", record.Hours())
+	}
+
+	_, err = a.kern.DeleteStoredRecord(ctx, "2", true)
+	if err == nil {
+		t.Error("deleting a record out of the list must fail")
+	}
+
+	_, err = a.kern.DeleteStoredRecord(ctx, record.ID()[:8], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a.records.records) != 0 {
+		t.Error("record must be deleted")
+	}
+}
+
+func TestDeletePoolRecord(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(20, 0))
+
+	a.addRecord(t, october(17), 2)
+
+	_, err := a.kern.DeleteStoredRecord(ctx, "1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.poolBalance() != 0 {
+		t.Errorf("pool balance %v, the deleted time must leave the pool", a.poolBalance())
+	}
+}
+
+func TestPoolRecordChangesRollBack(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(20, 0))
+
+	a.addRecord(t, october(17), 2)
+	a.pool.failSave = true
+
+	hours := 3.0
+	_, err := a.kern.EditStoredRecord(ctx, "1", RecordChanges{Hours: &hours}, true)
+	if err == nil {
+		t.Fatal("edit must fail")
+	}
+
+	_, err = a.kern.DeleteStoredRecord(ctx, "1", true)
+	if err == nil {
+		t.Fatal("delete must fail")
+	}
+
+	if got := a.hours(t, october(17), domain.Pool); got != 2 || a.poolBalance() != 2 {
+		t.Errorf("got %v pool hours and a balance of %v, want both unchanged", got, a.poolBalance())
+	}
+}
+
+func TestReport(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	a.addRecord(t, october(12), 8)
+	a.addRecord(t, october(13), 4)
+	a.addRecord(t, at(12, 0), 3)
+	_, err := a.kern.AddDaysOff(ctx, october(16), october(16), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := a.kern.WeekReport(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday and tuesday, wednesday only expects the time worked on it like on
+	// the debt, thursday is in the future and friday is a day off
+	expected := []float64{8, 8, 3, 0, 0, 0, 0}
+	for i, day := range report.Days {
+		if day.Expected != expected[i] {
+			t.Errorf("%s expects %v hours, want %v", day.Date.Format(time.DateOnly), day.Expected, expected[i])
+		}
+	}
+
+	if total := report.Total(); total.Worked() != 15 {
+		t.Errorf("worked %v hours, want 15", total.Worked())
+	}
+
+	_, err = a.kern.Report(ctx, october(14), october(12))
+	if err == nil {
+		t.Error("a report can't end before its start")
+	}
+}
+
+func TestDebt(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	_, err := a.kern.Debt(ctx, october(14), october(12))
+	if err == nil {
+		t.Error("debt can't end before its start")
+	}
+
+	if a.stats.debtArgs != nil {
+		t.Error("an invalid range must not get the debt")
+	}
+
+	a.config.schedule = domain.NewFlatSchedule(6)
+	a.config.holidays = []time.Time{october(12)}
+
+	_, err = a.kern.AddDaysOff(ctx, october(13), october(13), "Vacation")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.stats.debt = domain.NewDebt(october(12), october(14), at(12, 0), nil, a.config.schedule, nil)
+
+	debt, err := a.kern.Debt(ctx, october(12), october(14))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if debt != a.stats.debt {
+		t.Error("got another debt than the stats one")
+	}
+
+	args := a.stats.debtArgs
+	if !args.from.Equal(october(12)) || !args.to.Equal(october(14)) {
+		t.Errorf("got debt from %v to %v, want the given range", args.from, args.to)
+	}
+
+	if got := args.schedule.HoursOn(october(14)); got != 6 {
+		t.Errorf("got a schedule of %v hours, want the config one", got)
+	}
+
+	holidays := []string{}
+	for _, day := range args.holidays {
+		holidays = append(holidays, day.Format(time.DateOnly))
+	}
+
+	if strings.Join(holidays, ",") != "2026-10-12,2026-10-13" {
+		t.Errorf("got holidays %v, want the config holidays and the days off", holidays)
+	}
+}
+
+func TestDaysOff(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	_, err := a.kern.AddDaysOff(ctx, october(16), october(12), "")
+	if err == nil {
+		t.Error("days off can't end before their start")
+	}
+
+	added, err := a.kern.AddDaysOff(ctx, october(12), october(16), "Vacation")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if added != 5 {
+		t.Errorf("added %d days off, want 5", added)
+	}
+
+	deleted, err := a.kern.DeleteDaysOff(ctx, october(14), october(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted != 3 {
+		t.Errorf("deleted %d days off, want 3", deleted)
+	}
+
+	_, err = a.kern.DeleteDaysOff(ctx, october(14), october(20))
+	if err == nil {
+		t.Error("deleting without days off must fail")
+	}
+
+	days, err := a.kern.ListDaysOff(ctx, october(1), october(31))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(days) != 2 || days[0].Reason() != "Vacation" {
+		t.Errorf("got %d days off left, want 2", len(days))
+	}
+
+	// Holidays of the setting are listed once, before the stored ones
+	a.config.holidays = []time.Time{october(1), october(12), october(30).AddDate(0, 1, 0)}
+
+	days, err = a.kern.ListDaysOff(ctx, october(1), october(31))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(days) != 3 || !days[0].Date().Equal(october(1)) || days[0].Reason() != HolidaysSettingReason || days[1].Reason() != "Vacation" {
+		t.Errorf("unexpected days off %v", days)
+	}
+}
+
+func TestImportRecords(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	a.addRecord(t, october(12), 2)
+	existing, _ := a.records.GetAllByDate(ctx, october(12))
+
+	sameTime := domain.Must(domain.NewCloseRecord(existing[0].Date(), 2, noInfo))
+	newRecord := domain.Must(domain.NewCloseRecord(october(13).Add(9*time.Hour), 3, noInfo))
+
+	records := []*domain.Record{existing[0], sameTime, newRecord, newRecord}
+
+	summary, err := a.kern.ImportRecords(ctx, records, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Imported != 1 || summary.DuplicatedID != 2 || summary.DuplicatedTime != 1 || summary.Hours != 3 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	if len(a.records.records) != 1 {
+		t.Fatal("a dry run must not save records")
+	}
+
+	_, err = a.kern.ImportRecords(ctx, records, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a.records.records) != 2 {
+		t.Errorf("got %d records, want 2", len(a.records.records))
+	}
+}
+
+func TestImportRecordsToPool(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	// Saturday and a pool record on a working day
+	saturday := domain.Must(domain.NewCloseRecord(october(17).Add(9*time.Hour), 3, noInfo))
+	pool := domain.Must(domain.RecreateRecord("pool", october(13).Add(9*time.Hour), october(13).Add(11*time.Hour), domain.Pool.String(), noInfo))
+	working := domain.Must(domain.NewCloseRecord(october(13).Add(14*time.Hour), 4, noInfo))
+
+	summary, err := a.kern.ImportRecords(ctx, []*domain.Record{saturday, pool, working}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.PoolHours != 5 || len(a.pool.entries) != 0 {
+		t.Errorf("unexpected dry run summary %+v", summary)
+	}
+
+	_, err = a.kern.ImportRecords(ctx, []*domain.Record{saturday, pool, working}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balance := a.poolBalance(); balance != 5 {
+		t.Errorf("got pool balance %v, want 5", balance)
+	}
+
+	imported, _ := a.kern.records.Get(ctx, saturday.ID())
+	if !imported.IsPool() {
+		t.Error("a record on a saturday must go to the pool")
+	}
+
+	imported, _ = a.kern.records.Get(ctx, working.ID())
+	if imported.IsPool() {
+		t.Error("a record on a working day must keep its status")
+	}
+}
+
+func TestImportRollsBack(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	working := domain.Must(domain.NewCloseRecord(october(13).Add(9*time.Hour), 4, noInfo))
+	saturday := domain.Must(domain.NewCloseRecord(october(17).Add(9*time.Hour), 3, noInfo))
+
+	// The ledger entry of the saturday fails after the first record is saved
+	a.pool.failSave = true
+
+	_, err := a.kern.ImportRecords(ctx, []*domain.Record{working, saturday}, false)
+	if err == nil {
+		t.Fatal("import must fail")
+	}
+
+	if _, err := a.records.Get(ctx, working.ID()); err == nil {
+		t.Error("records of a failed import must not be saved")
+	}
+}
+
+func TestRecordFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApp(at(12, 0))
+
+	err := a.kern.AddTemplate(ctx, "standup", "Daily", "acme", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = a.kern.AddRecordFromTemplate(ctx, "standup", 0)
+	if err == nil {
+		t.Error("a template without duration needs hours")
+	}
+
+	_, err = a.kern.AddRecordFromTemplate(ctx, "missing", 1)
+	if err == nil {
+		t.Error("a missing template must fail")
+	}
+
+	hours, err := a.kern.AddRecordFromTemplate(ctx, "standup", 0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, _ := a.kern.ListRecords(ctx)
+	if hours != 0.25 || len(records) != 1 || records[0].Info().Description() != "Daily" {
+		t.Errorf("unexpected records %v", records)
+	}
+
+	err = a.kern.StartRecordFromTemplate(ctx, "standup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !a.track.open.Date().Equal(at(12, 0)) || a.track.open.Info().Project() != "acme" {
+		t.Error("open record must start now with the template info")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	a := newTestApp(at(12, 0))
+
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{text: "", want: at(12, 0)},
+		{text: "today", want: at(12, 0)},
+		{text: "yesterday", want: october(13)},
+		{text: "-2", want: october(12)},
+		{text: "3", want: october(17)},
+	}
+
+	for _, tt := range tests {
+		got, err := a.kern.ParseDate(tt.text)
+		if err != nil {
+			t.Fatalf("can't parse %q: %v", tt.text, err)
+		}
+
+		if !domain.IsSameDay(got, tt.want) {
+			t.Errorf("%q is %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
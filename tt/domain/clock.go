@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Source of the current time, replaced by a fixed one on tests
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,143 @@
+package domain
+
+import "time"
+
+// Hours expected by the schedule on a day against the hours recorded on it,
+// pool time is not counted as recorded and committed is part of recorded
+type DayBalance struct {
+	Date      time.Time
+	Expected  float64
+	Recorded  float64
+	Committed float64
+}
+
+// Positive when less time than expected was recorded
+func (d DayBalance) Debt() float64 {
+	return d.Expected - d.Recorded
+}
+
+// Missing at least a minute of the expected time
+func (d DayBalance) HasGap() bool {
+	return d.Debt() >= MinHours
+}
+
+func sumBalances(days []DayBalance) DayBalance {
+	total := DayBalance{}
+
+	for _, day := range days {
+		total.Expected += day.Expected
+		total.Recorded += day.Recorded
+		total.Committed += day.Committed
+	}
+
+	return total
+}
+
+type Debt struct {
+	From time.Time
+	To   time.Time
+	Days []DayBalance
+
+	// Current day and the hours the schedule expects on it
+	today      time.Time
+	todayHours float64
+}
+
+// Builds the balance of every day in the range. Nothing is expected in the
+// future and the current day only expects the time recorded on it, up to the
+// schedule, as it isn't known how much of the working day is gone. So today
+// never adds debt, only the time recorded over the schedule.
+func NewDebt(from, to, now time.Time, summaries []HoursSummary, schedule Schedule, holidays []time.Time) *Debt {
+	debt := &Debt{From: StartOfDay(from), To: StartOfDay(to), today: StartOfDay(now)}
+
+	days := map[string]*DayBalance{}
+	for date := debt.From; !date.After(debt.To); date = date.AddDate(0, 0, 1) {
+		expected := 0.0
+		if !date.After(debt.today) && !IsNonWorkingDay(date, schedule, holidays) {
+			expected = schedule.HoursOn(date)
+		}
+
+		if date.Equal(debt.today) {
+			debt.todayHours = expected
+		}
+
+		debt.Days = append(debt.Days, DayBalance{Date: date, Expected: expected})
+	}
+
+	for i := range debt.Days {
+		days[debt.Days[i].Date.Format(time.DateOnly)] = &debt.Days[i]
+	}
+
+	for _, s := range summaries {
+		day, ok := days[s.Date.Format(time.DateOnly)]
+		if !ok || s.Status == Pool {
+			continue
+		}
+
+		day.Recorded += s.Hours
+		if s.Status == Committed {
+			day.Committed += s.Hours
+		}
+	}
+
+	debt.updateToday()
+
+	return debt
+}
+
+func (d *Debt) updateToday() {
+	for i := range d.Days {
+		if d.Days[i].Date.Equal(d.today) {
+			d.Days[i].Expected = expectedToday(d.todayHours, d.Days[i].Recorded)
+		}
+	}
+}
+
+// Hours expected on the current day, the time recorded on it up to the
+// scheduled hours. The debt and the reports share it.
+func expectedToday(scheduled, recorded float64) float64 {
+	return min(scheduled, recorded)
+}
+
+// Adds time not stored yet, like the one of the open record, to a day
+func (d *Debt) AddRecorded(date time.Time, hours float64) {
+	for i := range d.Days {
+		if IsSameDay(d.Days[i].Date, date) {
+			d.Days[i].Recorded += hours
+			d.updateToday()
+			return
+		}
+	}
+}
+
+// Sum of all the days on the range
+func (d *Debt) Total() DayBalance {
+	return sumBalances(d.Days)
+}
+
+// Days of a week, from monday to sunday, within the range
+type DebtWeek struct {
+	From time.Time
+	Days []DayBalance
+}
+
+func (w DebtWeek) Total() DayBalance {
+	return sumBalances(w.Days)
+}
+
+// Days of the range grouped by week, the first and last weeks can be partial
+func (d *Debt) Weeks() []DebtWeek {
+	var weeks []DebtWeek
+
+	for _, day := range d.Days {
+		from, _ := WeekRange(day.Date)
+
+		if len(weeks) == 0 || !weeks[len(weeks)-1].From.Equal(from) {
+			weeks = append(weeks, DebtWeek{From: from})
+		}
+
+		weeks[len(weeks)-1].Days = append(weeks[len(weeks)-1].Days, day)
+	}
+
+	return weeks
+}
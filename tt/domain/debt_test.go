@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func date(day, hour int) time.Time {
+	return time.Date(2026, time.October, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestNewDebt(t *testing.T) {
+	// Wednesday at noon
+	now := date(14, 12)
+
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		summaries []HoursSummary
+		holidays  []time.Time
+		expected  float64
+		recorded  float64
+		days      int
+	}{
+		{
+			name:     "past week without records",
+			from:     date(5, 0),
+			to:       date(9, 0),
+			expected: 40,
+			days:     5,
+		},
+		{
+			name:     "weekend",
+			from:     date(10, 0),
+			to:       date(11, 0),
+			expected: 0,
+			days:     2,
+		},
+		{
+			name:     "holiday",
+			from:     date(5, 0),
+			to:       date(9, 0),
+			holidays: []time.Time{date(6, 0)},
+			expected: 32,
+			days:     5,
+		},
+		{
+			name:     "today without records",
+			from:     date(14, 0),
+			to:       date(14, 0),
+			expected: 0,
+			days:     1,
+		},
+		{
+			name: "today expects the recorded time",
+			from: date(14, 0),
+			to:   date(14, 0),
+			summaries: []HoursSummary{
+				{Date: date(14, 0), Status: Pending, Hours: 3},
+			},
+			expected: 3,
+			recorded: 3,
+			days:     1,
+		},
+		{
+			name: "today over the schedule",
+			from: date(14, 0),
+			to:   date(14, 0),
+			summaries: []HoursSummary{
+				{Date: date(14, 0), Status: Pending, Hours: 9.5},
+			},
+			expected: 8,
+			recorded: 9.5,
+			days:     1,
+		},
+		{
+			name:     "nothing expected in the future",
+			from:     date(15, 0),
+			to:       date(16, 0),
+			expected: 0,
+			days:     2,
+		},
+		{
+			name: "pool time is not recorded",
+			from: date(12, 0),
+			to:   date(12, 0),
+			summaries: []HoursSummary{
+				{Date: date(12, 0), Status: Pending, Hours: 3},
+				{Date: date(12, 0), Status: Committed, Hours: 4},
+				{Date: date(12, 0), Status: Pool, Hours: 2},
+			},
+			expected: 8,
+			recorded: 7,
+			days:     1,
+		},
+		{
+			name: "records out of the range are ignored",
+			from: date(12, 0),
+			to:   date(13, 0),
+			summaries: []HoursSummary{
+				{Date: date(9, 0), Status: Committed, Hours: 8},
+				{Date: date(13, 0), Status: Committed, Hours: 8},
+			},
+			expected: 16,
+			recorded: 8,
+			days:     2,
+		},
+		{
+			name: "time on non working days is recorded",
+			from: date(10, 0),
+			to:   date(10, 0),
+			summaries: []HoursSummary{
+				{Date: date(10, 0), Status: Pending, Hours: 2},
+			},
+			expected: 0,
+			recorded: 2,
+			days:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			debt := NewDebt(tt.from, tt.to, now, tt.summaries, NewFlatSchedule(8), tt.holidays)
+
+			if len(debt.Days) != tt.days {
+				t.Fatalf("got %d days, want %d", len(debt.Days), tt.days)
+			}
+
+			total := debt.Total()
+			if total.Expected != tt.expected {
+				t.Errorf("expected %v hours, want %v", total.Expected, tt.expected)
+			}
+
+			if total.Recorded != tt.recorded {
+				t.Errorf("recorded %v hours, want %v", total.Recorded, tt.recorded)
+			}
+
+			if total.Debt() != tt.expected-tt.recorded {
+				t.Errorf("debt %v, want %v", total.Debt(), tt.expected-tt.recorded)
+			}
+		})
+	}
+}
+
+func TestNewDebtScheduleChange(t *testing.T) {
+	change, err := NewWorkWeek(date(12, 0), [7]float64{0, 6, 6, 6, 6, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule := NewSchedule(NewFlatWorkWeek(time.Time{}, 8), []WorkWeek{change})
+
+	debt := NewDebt(date(8, 0), date(13, 0), date(20, 0), nil, schedule, nil)
+
+	// Thursday and Friday on the old schedule, Monday and Tuesday on the new one
+	if total := debt.Total(); total.Expected != 28 {
+		t.Errorf("expected %v hours, want 28", total.Expected)
+	}
+}
+
+func TestAddRecorded(t *testing.T) {
+	now := date(14, 18)
+
+	debt := NewDebt(date(13, 0), date(14, 0), now, nil, NewFlatSchedule(8), nil)
+	debt.AddRecorded(now, 1.5)
+	debt.AddRecorded(date(20, 0), 3)
+
+	if debt.Days[0].Recorded != 0 || debt.Days[1].Recorded != 1.5 {
+		t.Errorf("got %v and %v recorded, want 0 and 1.5", debt.Days[0].Recorded, debt.Days[1].Recorded)
+	}
+
+	if debt.Days[1].Expected != 1.5 {
+		t.Errorf("expected %v hours today, want 1.5", debt.Days[1].Expected)
+	}
+
+	// An 8 hours day doesn't show credit in the evening
+	debt.AddRecorded(now, 6.5)
+
+	if debt.Days[1].Expected != 8 || debt.Days[1].Debt() != 0 {
+		t.Errorf("expected %v hours today with a debt of %v, want 8 and 0", debt.Days[1].Expected, debt.Days[1].Debt())
+	}
+}
+
+func TestDebtWeeks(t *testing.T) {
+	summaries := []HoursSummary{
+		{Date: date(9, 0), Status: Committed, Hours: 8},
+		{Date: date(12, 0), Status: Committed, Hours: 5},
+		{Date: date(12, 0), Status: Pending, Hours: 3},
+		{Date: date(13, 0), Status: Pending, Hours: 7.5},
+	}
+
+	// Friday to Tuesday
+	debt := NewDebt(date(9, 0), date(13, 0), date(20, 0), summaries, NewFlatSchedule(8), nil)
+
+	weeks := debt.Weeks()
+	if len(weeks) != 2 {
+		t.Fatalf("got %d weeks, want 2", len(weeks))
+	}
+
+	tests := []struct {
+		week      DebtWeek
+		from      time.Time
+		days      int
+		expected  float64
+		recorded  float64
+		committed float64
+	}{
+		{week: weeks[0], from: date(5, 0), days: 3, expected: 8, recorded: 8, committed: 8},
+		{week: weeks[1], from: date(12, 0), days: 2, expected: 16, recorded: 15.5, committed: 5},
+	}
+
+	for _, tt := range tests {
+		total := tt.week.Total()
+
+		if !tt.week.From.Equal(tt.from) || len(tt.week.Days) != tt.days {
+			t.Errorf("week from %v with %d days, want %v with %d", tt.week.From, len(tt.week.Days), tt.from, tt.days)
+		}
+
+		if total.Expected != tt.expected || total.Recorded != tt.recorded || total.Committed != tt.committed {
+			t.Errorf("week from %v got %v/%v/%v, want %v/%v/%v", tt.from,
+				total.Expected, total.Recorded, total.Committed, tt.expected, tt.recorded, tt.committed)
+		}
+	}
+
+	gaps := []bool{false, false, false, false, true}
+	for i, day := range debt.Days {
+		if day.HasGap() != gaps[i] {
+			t.Errorf("%v has gap %v, want %v", day.Date.Format(time.DateOnly), day.HasGap(), gaps[i])
+		}
+	}
+}
@@ -2,7 +2,10 @@ package domain
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -11,7 +14,7 @@ type Hours float64
 
 func NewHours(hours float64) (Hours, error) {
 	if hours <= 0 {
-		return 0, fmt.Errorf("hours must be greater than 0")
+		return 0, Invalidf("hours must be greater than 0")
 	}
 
 	return Hours(hours), nil
@@ -21,29 +24,140 @@ func (r Hours) Float() float64 {
 	return float64(r)
 }
 
+type RecordStatus string
+
+const (
+	Pending   RecordStatus = "pending"
+	Committed RecordStatus = "committed"
+	Pool      RecordStatus = "pool"
+)
+
+func NewRecordStatus(status string) (RecordStatus, error) {
+	switch s := RecordStatus(status); s {
+	case Pending, Committed, Pool:
+		return s, nil
+	}
+
+	return "", Invalidf("unknown record status %q", status)
+}
+
+func (s RecordStatus) String() string {
+	return string(s)
+}
+
+// Describes what the time of a record was spent on
+type RecordInfo struct {
+	description string
+	project     string
+	tags        []string
+}
+
+func NewRecordInfo(description, project string, tags []string) RecordInfo {
+	info := RecordInfo{
+		description: strings.TrimSpace(description),
+		project:     strings.TrimSpace(project),
+	}
+
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag == "" || slices.Contains(info.tags, tag) {
+			continue
+		}
+
+		info.tags = append(info.tags, tag)
+	}
+
+	return info
+}
+
+// Parses a list of tags separated by commas or spaces
+func ParseTags(stags string) []string {
+	return strings.FieldsFunc(stags, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+func (i RecordInfo) Description() string {
+	return i.description
+}
+
+func (i RecordInfo) Project() string {
+	return i.project
+}
+
+func (i RecordInfo) Tags() []string {
+	return slices.Clone(i.tags)
+}
+
+func (i RecordInfo) IsEmpty() bool {
+	return i.description == "" && i.project == "" && len(i.tags) == 0
+}
+
+// Human readable version, description [project] #tag
+func (i RecordInfo) String() string {
+	parts := []string{}
+
+	if i.description != "" {
+		parts = append(parts, i.description)
+	}
+
+	if i.project != "" {
+		parts = append(parts, fmt.Sprintf("[%s]", i.project))
+	}
+
+	for _, tag := range i.tags {
+		parts = append(parts, fmt.Sprintf("#%s", tag))
+	}
+
+	return strings.Join(parts, " ")
+}
+
 type Record struct {
-	id    string
-	date  time.Time
-	hours Hours
+	id     string
+	date   time.Time
+	end    time.Time
+	hours  Hours
+	status RecordStatus
+	info   RecordInfo
 }
 
-func RecreateRecord(id string, date time.Time, hours float64) (*Record, error) {
-	hours = timeRounding(hours)
+func hoursToDuration(hours float64) time.Duration {
+	return time.Duration(hours * float64(time.Hour))
+}
+
+// Recreates a record from its start and end instants, the hours are the
+// interval rounded to the minute
+func RecreateRecord(id string, date, end time.Time, status string, info RecordInfo) (*Record, error) {
+	hours := timeRounding(end.Sub(date).Hours())
 
 	hoursValue, err := NewHours(hours)
 	if err != nil {
 		return nil, err
 	}
 
+	statusValue, err := NewRecordStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Record{
-		id:    id,
-		date:  date,
-		hours: hoursValue,
+		id:     id,
+		date:   date,
+		end:    end,
+		hours:  hoursValue,
+		status: statusValue,
+		info:   info,
 	}, nil
 }
 
-func NewCloseRecord(date time.Time, hours float64) (*Record, error) {
-	return RecreateRecord(uuid.New().String(), date, hours)
+// Creates a pending record starting at date and lasting the given hours
+func NewCloseRecord(date time.Time, hours float64, info RecordInfo) (*Record, error) {
+	return NewIntervalRecord(date, date.Add(hoursToDuration(timeRounding(hours))), info)
+}
+
+// Creates a pending record between two instants
+func NewIntervalRecord(start, end time.Time, info RecordInfo) (*Record, error) {
+	return RecreateRecord(uuid.New().String(), start, end, Pending.String(), info)
 }
 
 func (r *Record) ID() string {
@@ -54,10 +168,73 @@ func (r *Record) Hours() float64 {
 	return r.hours.Float()
 }
 
+// Short version of the id, enough to identify the record on a date
+func (r *Record) ShortID() string {
+	if len(r.id) < 8 {
+		return r.id
+	}
+
+	return r.id[:8]
+}
+
+// Start of the record
 func (r *Record) Date() time.Time {
 	return r.date
 }
 
+func (r *Record) End() time.Time {
+	return r.end
+}
+
+// Checks if the intervals of both records intersect
+func (r *Record) Overlaps(other *Record) bool {
+	return r.date.Before(other.end) && other.date.Before(r.end)
+}
+
+func (r *Record) Info() RecordInfo {
+	return r.info
+}
+
+func (r *Record) UpdateInfo(info RecordInfo) {
+	r.info = info
+}
+
+func (r *Record) Status() RecordStatus {
+	return r.status
+}
+
+func (r *Record) IsPending() bool {
+	return r.status == Pending
+}
+
+func (r *Record) IsCommitted() bool {
+	return r.status == Committed
+}
+
+func (r *Record) IsPool() bool {
+	return r.status == Pool
+}
+
+func (r *Record) Commit() error {
+	if r.status != Pending {
+		return Invalidf("only pending records can be committed, record is %s", r.status)
+	}
+
+	r.status = Committed
+
+	return nil
+}
+
+func (r *Record) SendToPool() error {
+	if r.status != Pending {
+		return Invalidf("only pending records can be sent to pool, record is %s", r.status)
+	}
+
+	r.status = Pool
+
+	return nil
+}
+
 func (r *Record) UpdateHours(hours float64) error {
 	hours = timeRounding(hours)
 
@@ -67,12 +244,40 @@ func (r *Record) UpdateHours(hours float64) error {
 	}
 
 	r.hours = hoursValue
+	r.end = r.date.Add(hoursToDuration(hours))
 
 	return nil
 }
 
+// Moves the start of the record keeping its duration
+func (r *Record) UpdateDate(date time.Time) {
+	r.end = date.Add(r.end.Sub(r.date))
+	r.date = date
+}
+
+// Keeps the given hours on the record and returns a new record, with the same
+// status, holding the remaining time. Returns nil if there is nothing left.
+func (r *Record) Split(hours float64) (*Record, error) {
+	hours = timeRounding(hours)
+
+	rest := timeRounding(r.Hours() - hours)
+	if rest <= 0 {
+		return nil, nil
+	}
+
+	end := r.end
+
+	err := r.UpdateHours(hours)
+	if err != nil {
+		return nil, err
+	}
+
+	return RecreateRecord(uuid.New().String(), r.end, end, r.status.String(), r.info)
+}
+
 type OpenRecord struct {
 	startDate time.Time
+	info      RecordInfo
 }
 
 func NewOpenRecord(date time.Time) *OpenRecord {
@@ -81,33 +286,213 @@ func NewOpenRecord(date time.Time) *OpenRecord {
 	}
 }
 
+func RecreateOpenRecord(date time.Time, info RecordInfo) *OpenRecord {
+	return &OpenRecord{
+		startDate: date,
+		info:      info,
+	}
+}
+
 func (r *OpenRecord) CloseRecord(endDate time.Time) (*Record, error) {
 	hours := endDate.Sub(r.startDate).Hours()
 	if hours <= 0 {
-		return nil, fmt.Errorf("record is empty")
+		return nil, Invalidf("record is empty")
 	}
 
-	return NewCloseRecord(r.startDate, hours)
+	return NewIntervalRecord(r.startDate, endDate, r.info)
 }
 
 func (r *OpenRecord) IsEmpty(endDate time.Time) bool {
-	return r.Hours() <= 0
+	return r.Hours(endDate) <= 0
 }
 
 func (r *OpenRecord) Date() time.Time {
 	return r.startDate
 }
 
-func (r *OpenRecord) Hours() float64 {
-	return timeRounding(time.Since(r.startDate).Hours())
+// Hours elapsed from the start of the record until now
+func (r *OpenRecord) Hours(now time.Time) float64 {
+	return timeRounding(now.Sub(r.startDate).Hours())
+}
+
+func (r *OpenRecord) Info() RecordInfo {
+	return r.info
+}
+
+func (r *OpenRecord) UpdateInfo(info RecordInfo) {
+	r.info = info
+}
+
+// Moves the start of the record, it can't start in the future
+func (r *OpenRecord) UpdateStart(date, now time.Time) error {
+	if date.After(now) {
+		return Invalidf("record can't start in the future")
+	}
+
+	r.startDate = date
+
+	return nil
+}
+
+type PoolMovement string
+
+const (
+	PoolIn  PoolMovement = "in"
+	PoolOut PoolMovement = "out"
+)
+
+func NewPoolMovement(movement string) (PoolMovement, error) {
+	switch m := PoolMovement(movement); m {
+	case PoolIn, PoolOut:
+		return m, nil
+	}
+
+	return "", Invalidf("unknown pool movement %q", movement)
+}
+
+func (m PoolMovement) String() string {
+	return string(m)
+}
+
+// Entry on the pool ledger, time going into the pool or poured out of it
+type PoolEntry struct {
+	id       string
+	date     time.Time
+	hours    Hours
+	movement PoolMovement
+}
+
+func RecreatePoolEntry(id string, date time.Time, hours float64, movement string) (*PoolEntry, error) {
+	hours = timeRounding(hours)
+
+	hoursValue, err := NewHours(hours)
+	if err != nil {
+		return nil, err
+	}
+
+	movementValue, err := NewPoolMovement(movement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PoolEntry{
+		id:       id,
+		date:     date,
+		hours:    hoursValue,
+		movement: movementValue,
+	}, nil
+}
+
+func NewPoolEntry(date time.Time, hours float64, movement PoolMovement) (*PoolEntry, error) {
+	return RecreatePoolEntry(uuid.New().String(), date, hours, movement.String())
+}
+
+func (e *PoolEntry) ID() string {
+	return e.id
+}
+
+func (e *PoolEntry) Date() time.Time {
+	return e.date
+}
+
+func (e *PoolEntry) Hours() float64 {
+	return e.hours.Float()
+}
+
+func (e *PoolEntry) Movement() PoolMovement {
+	return e.movement
+}
+
+// Hours with the sign of the movement, positive when going into the pool
+func (e *PoolEntry) Balance() float64 {
+	if e.movement == PoolOut {
+		return -e.Hours()
+	}
+
+	return e.Hours()
+}
+
+// Template for recurring records
+type Template struct {
+	name        string
+	description string
+	project     string
+	hours       float64
+}
+
+// Creates a template, hours are the default duration and 0 means no default
+func NewTemplate(name, description, project string, hours float64) (*Template, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, Invalidf("template name can't be empty")
+	}
+
+	if hours < 0 {
+		return nil, Invalidf("template hours can't be negative")
+	}
+
+	return &Template{
+		name:        name,
+		description: strings.TrimSpace(description),
+		project:     strings.TrimSpace(project),
+		hours:       timeRounding(hours),
+	}, nil
+}
+
+func (t *Template) Name() string {
+	return t.name
+}
+
+func (t *Template) Description() string {
+	return t.description
+}
+
+func (t *Template) Project() string {
+	return t.project
+}
+
+func (t *Template) Hours() float64 {
+	return t.hours
+}
+
+func (t *Template) Info() RecordInfo {
+	return NewRecordInfo(t.description, t.project, nil)
+}
+
+func (t *Template) HasHours() bool {
+	return t.hours > 0
+}
+
+// Non working day out of the schedule, like a holiday, vacation or sick day
+type DayOff struct {
+	date   time.Time
+	reason string
+}
+
+func NewDayOff(date time.Time, reason string) *DayOff {
+	return &DayOff{
+		date:   StartOfDay(date),
+		reason: strings.TrimSpace(reason),
+	}
+}
+
+func (d *DayOff) Date() time.Time {
+	return d.date
+}
+
+func (d *DayOff) Reason() string {
+	return d.reason
 }
 
 type PromptData interface {
 	RefreshData()
 	Wt() float64
+	Ct() float64
+	Pt() float64
 	Tt() float64
 	Dt() float64
 	IsWorking() bool
 	IsToday() bool
+	IsNonWorkingDay() bool
 	GetDate() time.Time
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error of an invalid input or of a rule of the domain, like a wrong duration
+// or a record already started, as opposed to a failure reading or writing the
+// data
+type InvalidError struct {
+	err error
+}
+
+func (e *InvalidError) Error() string {
+	return e.err.Error()
+}
+
+func (e *InvalidError) Unwrap() error {
+	return e.err
+}
+
+// Invalid error formatted like fmt.Errorf
+func Invalidf(format string, a ...any) error {
+	return &InvalidError{err: fmt.Errorf(format, a...)}
+}
+
+// Marks an error, like the one of a parser, as invalid input. A nil error
+// stays nil.
+func invalid(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &InvalidError{err: err}
+}
+
+// Reports if the error or any error it wraps is an invalid error
+func IsInvalid(err error) bool {
+	var invalid *InvalidError
+	return errors.As(err, &invalid)
+}
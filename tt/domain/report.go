@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Hours of a day grouped by project and status
+type HoursSummary struct {
+	Date    time.Time
+	Project string
+	Status  RecordStatus
+	Hours   float64
+}
+
+type ReportDay struct {
+	Date      time.Time
+	Expected  float64
+	Pending   float64
+	Committed float64
+	Pool      float64
+}
+
+// Time worked on the day, pool time is not counted
+func (d ReportDay) Worked() float64 {
+	return d.Pending + d.Committed
+}
+
+func (d ReportDay) Delta() float64 {
+	return d.Worked() - d.Expected
+}
+
+type ProjectHours struct {
+	Project string
+	Hours   float64
+}
+
+type Report struct {
+	From     time.Time
+	To       time.Time
+	Days     []ReportDay
+	Projects []ProjectHours
+}
+
+// Builds a report for every day in the range, expectedHours gives the hours
+// expected for each day. Like on the debt, the current day only expects the
+// time worked on it up to its expected hours.
+func NewReport(from, to, now time.Time, summaries []HoursSummary, expectedHours func(time.Time) float64) *Report {
+	report := &Report{From: StartOfDay(from), To: StartOfDay(to)}
+
+	days := map[string]*ReportDay{}
+	for date := report.From; !date.After(report.To); date = date.AddDate(0, 0, 1) {
+		report.Days = append(report.Days, ReportDay{Date: date, Expected: expectedHours(date)})
+	}
+
+	for i := range report.Days {
+		days[report.Days[i].Date.Format(time.DateOnly)] = &report.Days[i]
+	}
+
+	projects := map[string]float64{}
+
+	for _, s := range summaries {
+		day, ok := days[s.Date.Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+
+		switch s.Status {
+		case Pending:
+			day.Pending += s.Hours
+		case Committed:
+			day.Committed += s.Hours
+		case Pool:
+			day.Pool += s.Hours
+		}
+
+		projects[s.Project] += s.Hours
+	}
+
+	for i := range report.Days {
+		if report.Days[i].Date.Equal(StartOfDay(now)) {
+			report.Days[i].Expected = expectedToday(report.Days[i].Expected, report.Days[i].Worked())
+		}
+	}
+
+	for project, hours := range projects {
+		report.Projects = append(report.Projects, ProjectHours{Project: project, Hours: hours})
+	}
+
+	sort.Slice(report.Projects, func(i, j int) bool {
+		return report.Projects[i].Hours > report.Projects[j].Hours
+	})
+
+	return report
+}
+
+// Sum of all the days on the report
+func (r *Report) Total() ReportDay {
+	total := ReportDay{}
+
+	for _, day := range r.Days {
+		total.Expected += day.Expected
+		total.Pending += day.Pending
+		total.Committed += day.Committed
+		total.Pool += day.Pool
+	}
+
+	return total
+}
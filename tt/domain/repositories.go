@@ -2,12 +2,25 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var ErrRecordNotFound error = &InvalidError{err: errors.New("record not found")}
+
+// Setting of the configuration, source tells where its value comes from
+type ConfigEntry struct {
+	Key    string
+	Value  string
+	Source string
+}
+
 type ConfigRepository interface {
 	GetLogLevel() string
-	GetWorkTime() float64
+	GetSchedule() Schedule
+	GetHolidays() []time.Time
+	GetAll() []ConfigEntry
+	Set(key, value string) (ConfigEntry, error)
 }
 
 type RecordRepository interface {
@@ -15,13 +28,38 @@ type RecordRepository interface {
 	Delete(ctx context.Context, id string) error
 	Get(ctx context.Context, id string) (*Record, error)
 	GetAllByDate(ctx context.Context, date time.Time) ([]*Record, error)
+	GetAllByDateAndStatus(ctx context.Context, date time.Time, status RecordStatus) ([]*Record, error)
+	GetAllByStatus(ctx context.Context, status RecordStatus) ([]*Record, error)
+	GetAllInRange(ctx context.Context, from, to time.Time) ([]*Record, error)
+}
+
+type PoolRepository interface {
+	Save(ctx context.Context, e *PoolEntry) error
+	GetAll(ctx context.Context) ([]*PoolEntry, error)
+	GetBalance(ctx context.Context) (float64, error)
+}
+
+// Days off are kept one per date, saving a date again replaces its reason
+type DayOffRepository interface {
+	Save(ctx context.Context, days []*DayOff) error
+	Delete(ctx context.Context, from, to time.Time) (int, error)
+	GetAll(ctx context.Context) ([]*DayOff, error)
+	GetAllInRange(ctx context.Context, from, to time.Time) ([]*DayOff, error)
+}
+
+type TemplateRepository interface {
+	Save(ctx context.Context, t *Template) error
+	Get(ctx context.Context, name string) (*Template, error)
+	GetAll(ctx context.Context) ([]*Template, error)
 }
 
 type StatsRepository interface {
 	GetHoursByDate(ctx context.Context, date time.Time) (float64, error)
-	//GetHoursByStatus(ctx context.Context, status RecordStatus) (float64, error)
+	GetHoursByDateAndStatus(ctx context.Context, date time.Time, status RecordStatus) (float64, error)
+	GetHoursByStatus(ctx context.Context, status RecordStatus) (float64, error)
+	GetHoursSummary(ctx context.Context, from, to time.Time) ([]HoursSummary, error)
 	GetTrackedHours(ctx context.Context) (float64, error)
-	GetDebt(ctx context.Context, workingTime float64) (float64, error)
+	GetDebt(ctx context.Context, from, to time.Time, schedule Schedule, holidays []time.Time) (*Debt, error)
 }
 
 type TrackRepository interface {
@@ -30,3 +68,9 @@ type TrackRepository interface {
 	Delete(ctx context.Context) error
 	IsWorking(ctx context.Context) bool
 }
+
+// Runs f in a transaction, the repositories called with the context given to
+// f are part of it and nothing is written if f fails
+type Transactor interface {
+	WithTx(ctx context.Context, f func(ctx context.Context) error) error
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Hours expected on each weekday, indexed by time.Weekday, from a date on
+type WorkWeek struct {
+	From  time.Time
+	Hours [7]float64
+}
+
+// Work week with the same hours from monday to friday
+func NewFlatWorkWeek(from time.Time, hours float64) WorkWeek {
+	week := WorkWeek{From: from}
+	for day := time.Monday; day <= time.Friday; day++ {
+		week.Hours[day] = hours
+	}
+
+	return week
+}
+
+func NewWorkWeek(from time.Time, hours [7]float64) (WorkWeek, error) {
+	for day, h := range hours {
+		if h < 0 || h > 24 {
+			return WorkWeek{}, Invalidf("hours of %s must be between 0 and 24", time.Weekday(day))
+		}
+	}
+
+	return WorkWeek{From: StartOfDay(from), Hours: hours}, nil
+}
+
+// Working schedule, the base week applies until the first change. Changes
+// apply from their date on, so the expected hours of past days are kept when
+// the schedule changes.
+type Schedule struct {
+	base    WorkWeek
+	changes []WorkWeek
+}
+
+func NewSchedule(base WorkWeek, changes []WorkWeek) Schedule {
+	sorted := append([]WorkWeek{}, changes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].From.Before(sorted[j].From)
+	})
+
+	return Schedule{base: base, changes: sorted}
+}
+
+// Schedule with the same hours from monday to friday
+func NewFlatSchedule(hours float64) Schedule {
+	return NewSchedule(NewFlatWorkWeek(time.Time{}, hours), nil)
+}
+
+func (s Schedule) Base() WorkWeek {
+	return s.base
+}
+
+func (s Schedule) Changes() []WorkWeek {
+	return append([]WorkWeek{}, s.changes...)
+}
+
+// Work week in place on the date
+func (s Schedule) WeekOn(date time.Time) WorkWeek {
+	week := s.base
+
+	day := StartOfDay(date)
+	for _, change := range s.changes {
+		if change.From.After(day) {
+			break
+		}
+
+		week = change
+	}
+
+	return week
+}
+
+// Hours expected on the date, holidays are not taken into account
+func (s Schedule) HoursOn(date time.Time) float64 {
+	return s.WeekOn(date).Hours[date.Weekday()]
+}
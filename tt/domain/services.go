@@ -14,10 +14,37 @@ func FormatDuration(d float64) string {
 	return fmt.Sprintf("%d:%02d", h, m)
 }
 
+// Same as FormatDuration but always showing the sign
+func FormatSignedDuration(d float64) string {
+	if d < 0 {
+		return fmt.Sprintf("-%s", FormatDuration(-d))
+	}
+
+	return fmt.Sprintf("+%s", FormatDuration(d))
+}
+
+// Smallest amount of hours that can be recorded, one minute
+const MinHours = float64(1) / 60
+
 // Defines how the tasks time is rounded
 func timeRounding(time float64) float64 {
-	fact := float64(1) / 60
-	return math.Round(time/fact) * fact
+	return math.Round(time/MinHours) * MinHours
+}
+
+// Ids of the records overlapping any other record on the list
+func Overlapping(records []*Record) map[string]bool {
+	overlapping := map[string]bool{}
+
+	for i, a := range records {
+		for _, b := range records[i+1:] {
+			if a.Overlaps(b) {
+				overlapping[a.ID()] = true
+				overlapping[b.ID()] = true
+			}
+		}
+	}
+
+	return overlapping
 }
 
 type Selectable interface {
@@ -32,24 +59,24 @@ type DateState interface {
 }
 
 type DateInMemory struct {
-	date *time.Time
+	clock Clock
+	date  *time.Time
 }
 
-func NewDateInMemory() *DateInMemory {
-	return &DateInMemory{}
+func NewDateInMemory(clock Clock) *DateInMemory {
+	return &DateInMemory{clock: clock}
 }
 
 func (d *DateInMemory) Get() time.Time {
 	if d.date == nil {
-		return time.Now()
+		return d.clock.Now()
 	}
 
 	return *d.date
 }
 
 func (d *DateInMemory) Set(date time.Time) {
-	now := time.Now()
-	if date.Year() == now.Year() && date.Month() == now.Month() && date.Day() == now.Day() {
+	if IsSameDay(date, d.clock.Now()) {
 		d.date = nil
 		return
 	}
@@ -64,6 +91,45 @@ func IsWeekend(date time.Time) bool {
 	return date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
 }
 
+func StartOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// Monday and Sunday of the week of the date
+func WeekRange(date time.Time) (time.Time, time.Time) {
+	offset := (int(date.Weekday()) + 6) % 7
+	from := StartOfDay(date).AddDate(0, 0, -offset)
+
+	return from, from.AddDate(0, 0, 6)
+}
+
+// First and last day of the month of the date
+func MonthRange(date time.Time) (time.Time, time.Time) {
+	from := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+
+	return from, from.AddDate(0, 1, -1)
+}
+
+func IsSameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
+
+// Days without hours on the schedule, like weekends, and holidays are non
+// working days, time recorded on them goes to the pool
+func IsNonWorkingDay(date time.Time, schedule Schedule, holidays []time.Time) bool {
+	if schedule.HoursOn(date) <= 0 {
+		return true
+	}
+
+	for _, holiday := range holidays {
+		if IsSameDay(date, holiday) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type Displayer interface {
 	Show(string)
 }
@@ -9,7 +9,8 @@ import (
 )
 
 func ParseHour(shour string) (time.Time, error) {
-	return time.Parse("15:04", shour)
+	hour, err := time.Parse("15:04", shour)
+	return hour, invalid(err)
 }
 
 func SetDate(hour time.Time, date time.Time) time.Time {
@@ -23,42 +24,79 @@ func ParseDuration(sd string) (float64, error) {
 	// Split the string into hours and minutes
 	parts := strings.Split(sd, ":")
 	if len(parts) != 2 {
-		return 0, fmt.Errorf("wrong duration format")
+		return 0, Invalidf("wrong duration format")
 	}
 
 	hours, err := strconv.Atoi(parts[0])
 	if err != nil {
-		return 0, err
+		return 0, invalid(err)
 	}
 
 	minutes, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return 0, err
+		return 0, invalid(err)
 	}
 
 	return float64(hours) + float64(minutes)/60, nil
 }
 
-func GetDateFromText(sdate string) (time.Time, error) {
+// Parses an amount of hours, either as a duration (h:mm) or as a decimal number.
+// Empty or "all" means no limit and is returned as 0.
+func ParseAmount(sa string) (float64, error) {
+	switch sa {
+	case "", "all":
+		return 0, nil
+	}
+
+	if strings.Contains(sa, ":") {
+		return ParseDuration(sa)
+	}
+
+	amount, err := strconv.ParseFloat(sa, 64)
+	if err != nil {
+		return 0, Invalidf("wrong amount format")
+	}
+
+	if amount < 0 {
+		return 0, Invalidf("amount can't be negative")
+	}
+
+	return amount, nil
+}
+
+// Parses the optional force argument, empty means no force
+func ParseForce(sf string) (bool, error) {
+	switch strings.ToLower(sf) {
+	case "", "n", "no", "false":
+		return false, nil
+	case "f", "force", "y", "yes", "true":
+		return true, nil
+	}
+
+	return false, Invalidf("wrong force value, use force or leave it empty")
+}
+
+// Parses a date as yy-mm-dd, today, yesterday or a number of days from now
+func GetDateFromText(sdate string, now time.Time) (time.Time, error) {
 	var date time.Time
 
 	switch sdate {
-	case "now", "today", "", time.Now().Format("06-01-02"):
-		return time.Now(), nil
+	case "now", "today", "", now.Format("06-01-02"):
+		return now, nil
 	case "yesterday":
-		date = time.Now().AddDate(0, 0, -1)
+		date = now.AddDate(0, 0, -1)
 	default:
 		val, err := strconv.Atoi(sdate)
 
 		if err == nil {
-			pdate := time.Now().AddDate(0, 0, val)
+			pdate := now.AddDate(0, 0, val)
 			return pdate, nil
 		}
 
 		pdate, err := time.Parse("06-01-02", sdate)
 
 		if err != nil {
-			return pdate, err
+			return pdate, invalid(err)
 		}
 
 		date = pdate
@@ -67,6 +105,22 @@ func GetDateFromText(sdate string) (time.Time, error) {
 	return date, nil
 }
 
+// Parses a month as yyyy-mm or yy-mm, empty means the current month
+func ParseMonth(smonth string, now time.Time) (time.Time, error) {
+	if smonth == "" {
+		return now, nil
+	}
+
+	for _, layout := range []string{"2006-01", "06-01"} {
+		month, err := time.ParseInLocation(layout, smonth, time.Local)
+		if err == nil {
+			return month, nil
+		}
+	}
+
+	return time.Time{}, Invalidf("wrong month format, use yyyy-mm")
+}
+
 func SprintList(list []string) string {
 	buf := bytes.NewBufferString("")
 
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/export"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HTTP JSON API over the app use cases, every request needs the token as
+// Authorization: Bearer <token>
+type Server struct {
+	kern   *app.App
+	token  string
+	routes map[string]map[string]handlerFunc
+}
+
+type handlerFunc func(*http.Request) (any, error)
+
+func NewServer(kern *app.App, token string) *Server {
+	s := &Server{
+		kern:   kern,
+		token:  token,
+		routes: map[string]map[string]handlerFunc{},
+	}
+
+	s.handle(http.MethodGet, "/status", s.status)
+	s.handle(http.MethodGet, "/records", s.listRecords)
+	s.handle(http.MethodPost, "/records", s.addRecord)
+	s.handle(http.MethodGet, "/records/current", s.currentRecord)
+	s.handle(http.MethodPost, "/records/start", s.startRecord)
+	s.handle(http.MethodPost, "/records/stop", s.stopRecord)
+	s.handle(http.MethodGet, "/report", s.report)
+
+	return s
+}
+
+// Random token for a new server
+func NewToken() (string, error) {
+	buf := make([]byte, 32)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", fmt.Errorf("can't generate token, %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid or missing token"))
+		return
+	}
+
+	methods, ok := s.routes[r.URL.Path]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s not found", r.URL.Path))
+		return
+	}
+
+	handler, ok := methods[r.Method]
+	if !ok {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	// Use cases run one at a time, like the ones of the gui and the daemon
+	s.kern.Lock()
+	resp, err := handler(r)
+	s.kern.Unlock()
+
+	if err != nil {
+		if domain.IsInvalid(err) {
+			logrus.Debugf("api %s %s: %v", r.Method, r.URL.Path, err)
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		logrus.Errorf("api %s %s: %v", r.Method, r.URL.Path, err)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || s.token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) handle(method, path string, handler handlerFunc) {
+	if s.routes[path] == nil {
+		s.routes[path] = map[string]handlerFunc{}
+	}
+
+	s.routes[path][method] = handler
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		logrus.Errorf("api can't write response: %v", err)
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, errorResponse{Error: err.Error()})
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		return domain.Invalidf("invalid body, %w", err)
+	}
+
+	return nil
+}
+
+// Parses an optional query date using the same formats as the cli
+func (s *Server) queryDate(r *http.Request, name string, def time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	date, err := s.kern.ParseDate(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s, %w", name, err)
+	}
+
+	return date, nil
+}
+
+func (s *Server) status(r *http.Request) (any, error) {
+	data, err := s.kern.LoadPromptData(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return export.NewStatus(data), nil
+}
+
+func (s *Server) listRecords(r *http.Request) (any, error) {
+	from, err := s.queryDate(r, "from", s.kern.GetDate())
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.queryDate(r, "to", from)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.kern.ListRecordsInRange(r.Context(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return export.NewListRecords(records), nil
+}
+
+type recordInfo struct {
+	Description string   `json:"description"`
+	Project     string   `json:"project"`
+	Tags        []string `json:"tags"`
+}
+
+func (i recordInfo) toDomain() domain.RecordInfo {
+	return domain.NewRecordInfo(i.Description, i.Project, i.Tags)
+}
+
+type hoursResponse struct {
+	Hours float64 `json:"hours"`
+}
+
+type addRecordRequest struct {
+	recordInfo
+	Hours string `json:"hours"`
+}
+
+func (s *Server) addRecord(r *http.Request) (any, error) {
+	req := addRecordRequest{}
+
+	err := decodeBody(r, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := domain.ParseDuration(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.kern.AddRecord(r.Context(), hours, req.toDomain())
+	if err != nil {
+		return nil, err
+	}
+
+	return hoursResponse{Hours: hours}, nil
+}
+
+type startRecordRequest struct {
+	recordInfo
+	At string `json:"at"`
+}
+
+func (s *Server) startRecord(r *http.Request) (any, error) {
+	req := startRecordRequest{}
+
+	err := decodeBody(r, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.At == "" {
+		err = s.kern.StartRecord(r.Context(), req.toDomain())
+	} else {
+		var at time.Time
+
+		at, err = domain.ParseHour(req.At)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.kern.StartRecordAt(r.Context(), at, req.toDomain())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.currentRecord(r)
+}
+
+type stopRecordRequest struct {
+	At string `json:"at"`
+}
+
+func (s *Server) stopRecord(r *http.Request) (any, error) {
+	req := stopRecordRequest{}
+
+	err := decodeBody(r, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	var hours float64
+
+	if req.At == "" {
+		hours, err = s.kern.StopRecord(r.Context())
+	} else {
+		var at time.Time
+
+		at, err = domain.ParseHour(req.At)
+		if err != nil {
+			return nil, err
+		}
+
+		hours, err = s.kern.StopRecordAt(r.Context(), at)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hoursResponse{Hours: hours}, nil
+}
+
+func (s *Server) currentRecord(r *http.Request) (any, error) {
+	record, err := s.kern.ViewRecord(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return export.NewOpenRecord(record, s.kern.Now()), nil
+}
+
+func (s *Server) report(r *http.Request) (any, error) {
+	weekFrom, weekTo := domain.WeekRange(s.kern.GetDate())
+
+	from, err := s.queryDate(r, "from", weekFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.queryDate(r, "to", weekTo)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.kern.Report(r.Context(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return export.NewReport(report), nil
+}
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/export"
+	"varmijo/time-tracker/tt/infrastructure/repositories"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const testToken = "secret"
+
+type testConfig struct{}
+
+func (testConfig) GetLogLevel() string          { return "error" }
+func (testConfig) GetSchedule() domain.Schedule { return domain.NewFlatSchedule(8) }
+func (testConfig) GetHolidays() []time.Time     { return nil }
+func (testConfig) GetAll() []domain.ConfigEntry { return nil }
+func (testConfig) Set(key, value string) (domain.ConfigEntry, error) {
+	return domain.ConfigEntry{}, nil
+}
+
+// Clock of the tests, a wednesday at noon unless advanced
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, time.October, 14, 12, 0, 0, 0, time.Local)}
+}
+
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := repositories.OpenSQLiteDB(filepath.Join(t.TempDir(), "tt.db"))
+	if err != nil {
+		t.Fatalf("can't open db: %v", err)
+	}
+	t.Cleanup(func() { repositories.CloseSQLiteDB(db) })
+
+	return db
+}
+
+func newTestApp(db *sqlx.DB, clock domain.Clock) *app.App {
+	return app.NewApp(testConfig{},
+		repositories.NewSQLiteRecordRepository(db),
+		repositories.NewSQLiteTrackRepository(db),
+		repositories.NewSQLiteStatsRepository(db, clock),
+		repositories.NewSQLitePoolRepository(db),
+		repositories.NewSQLiteTemplateRepository(db),
+		repositories.NewSQLiteDayOffRepository(db),
+		repositories.NewSQLiteTransactor(db),
+		clock)
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, token string, body any) (int, []byte) {
+	t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&reader).Encode(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, srv.URL+path, &reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var out bytes.Buffer
+	_, err = out.ReadFrom(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, out.Bytes()
+}
+
+func decode[T any](t *testing.T, data []byte) T {
+	t.Helper()
+
+	var v T
+
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		t.Fatalf("invalid response %s: %v", data, err)
+	}
+
+	return v
+}
+
+func TestRouting(t *testing.T) {
+	srv := httptest.NewServer(NewServer(newTestApp(openTestDB(t), newTestClock()), testToken))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{"missing token", http.MethodGet, "/records", "", http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, "/records", "other", http.StatusUnauthorized},
+		{"unknown path", http.MethodGet, "/nothing", testToken, http.StatusNotFound},
+		{"wrong method", http.MethodDelete, "/records", testToken, http.StatusMethodNotAllowed},
+		{"list", http.MethodGet, "/records", testToken, http.StatusOK},
+		{"invalid date", http.MethodGet, "/records?from=someday", testToken, http.StatusBadRequest},
+		{"report", http.MethodGet, "/report", testToken, http.StatusOK},
+		{"no current record", http.MethodGet, "/records/current", testToken, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := do(t, srv, tt.method, tt.path, tt.token, nil)
+			if code != tt.code {
+				t.Errorf("got %d, want %d: %s", code, tt.code, body)
+			}
+		})
+	}
+}
+
+func TestEmptyServerToken(t *testing.T) {
+	srv := httptest.NewServer(NewServer(newTestApp(openTestDB(t), newTestClock()), ""))
+	defer srv.Close()
+
+	code, _ := do(t, srv, http.MethodGet, "/records", "", nil)
+	if code != http.StatusUnauthorized {
+		t.Errorf("got %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	clock := newTestClock()
+	kern := newTestApp(openTestDB(t), clock)
+
+	srv := httptest.NewServer(NewServer(kern, testToken))
+	defer srv.Close()
+
+	// Tuesday, records added on a past date are pending
+	day := clock.now.AddDate(0, 0, -1)
+
+	err := kern.ChangeDate(context.Background(), day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, body := do(t, srv, http.MethodPost, "/records", testToken, map[string]any{
+		"hours":       "1:30",
+		"description": "Review",
+		"project":     "acme",
+		"tags":        []string{"docs"},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("add: got %d: %s", code, body)
+	}
+
+	code, body = do(t, srv, http.MethodPost, "/records", testToken, map[string]any{"hours": "1.5"})
+	if code != http.StatusBadRequest {
+		t.Errorf("add invalid hours: got %d: %s", code, body)
+	}
+
+	code, body = do(t, srv, http.MethodGet, "/records?from="+day.Format("06-01-02"), testToken, nil)
+	if code != http.StatusOK {
+		t.Fatalf("list: got %d: %s", code, body)
+	}
+
+	records := decode[[]export.ListRecord](t, body)
+	if len(records) != 1 {
+		t.Fatalf("list: got %d records, want 1", len(records))
+	}
+
+	if got := records[0]; got.Hours != 1.5 || got.Description != "Review" || got.Project != "acme" || got.Status != domain.Pending.String() {
+		t.Errorf("list: unexpected record %+v", got)
+	}
+
+	code, body = do(t, srv, http.MethodGet, "/status", testToken, nil)
+	if code != http.StatusOK {
+		t.Fatalf("status: got %d: %s", code, body)
+	}
+
+	if got := decode[export.Status](t, body); got.Worked != 1.5 || got.Today || got.Working {
+		t.Errorf("status: unexpected %+v", got)
+	}
+
+	err = kern.ChangeDate(context.Background(), clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, body = do(t, srv, http.MethodPost, "/records/start", testToken, map[string]any{"description": "Standup"})
+	if code != http.StatusOK {
+		t.Fatalf("start: got %d: %s", code, body)
+	}
+
+	if got := decode[export.OpenRecord](t, body); got.Description != "Standup" {
+		t.Errorf("start: unexpected %+v", got)
+	}
+
+	code, body = do(t, srv, http.MethodPost, "/records/start", testToken, nil)
+	if code != http.StatusBadRequest {
+		t.Errorf("start twice: got %d: %s", code, body)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+
+	code, body = do(t, srv, http.MethodPost, "/records/stop", testToken, nil)
+	if code != http.StatusOK {
+		t.Fatalf("stop: got %d: %s", code, body)
+	}
+
+	if got := decode[hoursResponse](t, body); got.Hours != 1 {
+		t.Errorf("stop: got %v hours, want 1", got.Hours)
+	}
+
+	code, body = do(t, srv, http.MethodGet, "/records/current", testToken, nil)
+	if code != http.StatusBadRequest {
+		t.Errorf("current after stop: got %d: %s", code, body)
+	}
+}
+
+func TestStorageErrors(t *testing.T) {
+	db := openTestDB(t)
+
+	srv := httptest.NewServer(NewServer(newTestApp(db, newTestClock()), testToken))
+	defer srv.Close()
+
+	err := repositories.CloseSQLiteDB(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, body := do(t, srv, http.MethodGet, "/records", testToken, nil)
+	if code != http.StatusInternalServerError {
+		t.Errorf("got %d, want %d: %s", code, http.StatusInternalServerError, body)
+	}
+
+	code, body = do(t, srv, http.MethodGet, "/status", testToken, nil)
+	if code != http.StatusInternalServerError {
+		t.Errorf("status: got %d, want %d: %s", code, http.StatusInternalServerError, body)
+	}
+
+	code, body = do(t, srv, http.MethodPost, "/records", testToken, map[string]any{"hours": "1.5"})
+	if code != http.StatusBadRequest {
+		t.Errorf("invalid hours: got %d, want %d: %s", code, http.StatusBadRequest, body)
+	}
+}
+
+func TestConcurrentStarts(t *testing.T) {
+	srv := httptest.NewServer(NewServer(newTestApp(openTestDB(t), newTestClock()), testToken))
+	defer srv.Close()
+
+	codes := make(chan int, 10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < cap(codes); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			code, _ := do(t, srv, http.MethodPost, "/records/start", testToken, nil)
+			codes <- code
+		}()
+	}
+
+	wg.Wait()
+	close(codes)
+
+	started := 0
+	for code := range codes {
+		if code == http.StatusOK {
+			started++
+		}
+	}
+
+	if started != 1 {
+		t.Errorf("%d records started, want 1", started)
+	}
+}
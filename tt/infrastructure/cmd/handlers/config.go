@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+)
+
+func (h *Handlers) ShowConfig(r *repl.Request, w repl.IO) {
+	buf := bytes.NewBufferString("")
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+
+	for _, entry := range h.kern.ListConfig() {
+		fmt.Fprintf(tw, "%s\t%s\t(%s)\n", entry.Key, entry.Value, entry.Source)
+	}
+
+	tw.Flush()
+
+	repl.PrintHighightedMsg(w, "Config")
+	repl.PrintPlain(w, strings.TrimRight(buf.String(), "\n"))
+}
+
+func (h *Handlers) SetConfig(r *repl.Request, w repl.IO) {
+	key, err := r.Arg("Key")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	value, err := r.Arg("Value")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	entry, err := h.kern.SetConfig(r.Ctx(), key, value)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if strings.HasPrefix(entry.Source, "TT_") {
+		repl.PrintInfoMsg(w, fmt.Sprintf("%s saved, %s overrides it with %s!", entry.Key, entry.Source, entry.Value))
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%s set to %s!", entry.Key, entry.Value))
+}
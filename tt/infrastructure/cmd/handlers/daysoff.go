@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/importer"
+)
+
+// Parses a date or a from..to range of dates
+func (h *Handlers) parseDayRange(value string) (time.Time, time.Time, error) {
+	sfrom, sto, isRange := strings.Cut(value, "..")
+
+	from, err := h.kern.ParseDate(strings.TrimSpace(sfrom))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !isRange {
+		return from, from, nil
+	}
+
+	to, err := h.kern.ParseDate(strings.TrimSpace(sto))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return from, to, nil
+}
+
+func (h *Handlers) AddDaysOff(r *repl.Request, w repl.IO) {
+	sdate, err := r.Arg("Date")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	from, to, err := h.parseDayRange(sdate)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	reason, _ := r.Arg("Reason")
+
+	days, err := h.kern.AddDaysOff(r.Ctx(), from, to, reason)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%d days off saved!", days))
+}
+
+func (h *Handlers) DeleteDaysOff(r *repl.Request, w repl.IO) {
+	sdate, err := r.Arg("Date")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	from, to, err := h.parseDayRange(sdate)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	days, err := h.kern.DeleteDaysOff(r.Ctx(), from, to)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%d days off deleted!", days))
+}
+
+// Lists the days off of a range, the year of the current date by default
+func (h *Handlers) ListDaysOff(r *repl.Request, w repl.IO) {
+	date := h.kern.GetDate()
+	from := time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+	to := from.AddDate(1, 0, -1)
+
+	if sdate, _ := r.Arg("Date"); sdate != "" {
+		var err error
+
+		from, to, err = h.parseDayRange(sdate)
+		if err != nil {
+			repl.PrintError(w, err)
+			return
+		}
+	}
+
+	days, err := h.kern.ListDaysOff(r.Ctx(), from, to)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	list := make([]string, len(days))
+	for i, day := range days {
+		list[i] = strings.TrimSpace(fmt.Sprintf("%s %s %s", day.Date().Format("06-01-02"), day.Date().Format("Mon"), day.Reason()))
+	}
+
+	repl.PrintHighightedMsg(w, fmt.Sprintf("Days off %s to %s", from.Format("06-01-02"), to.Format("06-01-02")))
+	repl.PrintPlain(w, domain.SprintList(list))
+}
+
+func (h *Handlers) ImportDaysOff(r *repl.Request, w repl.IO) {
+	path, err := r.Arg("File")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+	defer file.Close()
+
+	// Yearly events without an end are repeated up to the end of next year
+	now := h.kern.Now()
+	until := time.Date(now.Year()+1, time.December, 31, 0, 0, 0, 0, now.Location())
+
+	days, lineErrors, err := importer.ReadDaysOff(file, until)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if len(lineErrors) > 0 {
+		lines := []string{}
+
+		for i, lineError := range lineErrors {
+			if i == maxLineErrors {
+				lines = append(lines, fmt.Sprintf("... and %d more", len(lineErrors)-maxLineErrors))
+				break
+			}
+
+			lines = append(lines, lineError.Error())
+		}
+
+		repl.PrintHighightedMsg(w, "Skipped events")
+		repl.PrintPlain(w, strings.Join(lines, "\n"))
+	}
+
+	imported, err := h.kern.ImportDaysOff(r.Ctx(), days)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%d days off imported!", imported))
+}
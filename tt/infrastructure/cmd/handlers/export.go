@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/export"
+)
+
+// Reads the optional From and To args, both default to the current date and
+// To defaults to From when only the start is given
+func (h *Handlers) parseRange(r *repl.Request) (time.Time, time.Time, error) {
+	from, err := repl.ParseOptionalArg(r, "From", h.kern.ParseDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to, err := repl.ParseOptionalArg(r, "To", h.kern.ParseDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if from == nil {
+		date := h.kern.GetDate()
+		from = &date
+	}
+
+	if to == nil {
+		to = from
+	}
+
+	return *from, *to, nil
+}
+
+func (h *Handlers) Export(r *repl.Request, w repl.IO) {
+	format, err := r.Arg("Format")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	path, err := r.Arg("File")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if path == "" {
+		repl.PrintErrorMsg(w, "file is required")
+		return
+	}
+
+	err = export.CheckFormat(format)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	from, to, err := h.parseRange(r)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	records, err := h.kern.ListRecordsInRange(r.Ctx(), from, to)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	// A - prints the export, so one-shot commands can pipe it
+	if path == "-" {
+		var out strings.Builder
+
+		err = export.Write(&out, format, records, h.kern.Now())
+		if err != nil {
+			repl.PrintError(w, err)
+			return
+		}
+
+		repl.PrintPlain(w, strings.TrimSuffix(out.String(), "\n"))
+		return
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+	defer file.Close()
+
+	err = export.Write(file, format, records, h.kern.Now())
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%d records exported to %s!", len(records), path))
+}
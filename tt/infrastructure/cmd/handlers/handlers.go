@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"fmt"
+	"strings"
 	"varmijo/time-tracker/tt/app"
 	"varmijo/time-tracker/tt/domain"
 	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/export"
 
 	"varmijo/time-tracker/tt/infrastructure/cmd/repl/mux"
 )
@@ -14,7 +16,7 @@ type Handlers struct {
 	mux  *mux.Mux
 }
 
-func NewHandlers(kern *app.App) repl.Handler {
+func NewHandlers(kern *app.App) *mux.Mux {
 	h := &Handlers{
 		kern: kern,
 		mux:  mux.NewMux(),
@@ -30,6 +32,35 @@ func (h *Handlers) GetMux() *mux.Mux {
 	return h.mux
 }
 
+// Reads the optional description, project and tags args
+func parseInfo(r *repl.Request) domain.RecordInfo {
+	description, _ := r.Arg("Description")
+	project, _ := r.Arg("Project")
+	tags, _ := r.Arg("Tags")
+
+	return domain.NewRecordInfo(description, project, domain.ParseTags(tags))
+}
+
+// Reads the optional description, project and tags args, empty ones are kept
+func parseInfoChanges(r *repl.Request) app.InfoChanges {
+	var changes app.InfoChanges
+
+	if description, _ := r.Arg("Description"); description != "" {
+		changes.Description = &description
+	}
+
+	if project, _ := r.Arg("Project"); project != "" {
+		changes.Project = &project
+	}
+
+	if stags, _ := r.Arg("Tags"); stags != "" {
+		tags := domain.ParseTags(stags)
+		changes.Tags = &tags
+	}
+
+	return changes
+}
+
 func (h *Handlers) AddRecord(r *repl.Request, w repl.IO) {
 	phours, err := repl.ParseArg(r, "Hours", domain.ParseDuration)
 	if err != nil {
@@ -37,7 +68,7 @@ func (h *Handlers) AddRecord(r *repl.Request, w repl.IO) {
 		return
 	}
 
-	err = h.kern.AddRecord(r.Ctx(), phours)
+	err = h.kern.AddRecord(r.Ctx(), phours, parseInfo(r))
 	if err != nil {
 		repl.PrintError(w, err)
 		return
@@ -47,7 +78,7 @@ func (h *Handlers) AddRecord(r *repl.Request, w repl.IO) {
 }
 
 func (h *Handlers) StartRecord(r *repl.Request, w repl.IO) {
-	err := h.kern.StartRecord(r.Ctx())
+	err := h.kern.StartRecord(r.Ctx(), parseInfo(r))
 	if err != nil {
 		repl.PrintError(w, err)
 		return
@@ -63,7 +94,7 @@ func (h *Handlers) StartRecordAt(r *repl.Request, w repl.IO) {
 		return
 	}
 
-	err = h.kern.StartRecordAt(r.Ctx(), pat)
+	err = h.kern.StartRecordAt(r.Ctx(), pat, parseInfo(r))
 	if err != nil {
 		repl.PrintError(w, err)
 		return
@@ -109,8 +140,157 @@ func (h *Handlers) DropRecord(r *repl.Request, w repl.IO) {
 	repl.PrintInfoMsg(w, fmt.Sprintf("%0.2f hours dropped!", hours))
 }
 
+func (h *Handlers) ViewRecord(r *repl.Request, w repl.IO) {
+	openRecord, err := h.kern.ViewRecord(r.Ctx())
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if r.Flag("json") {
+		printJSON(w, export.NewOpenRecord(openRecord, h.kern.Now()))
+		return
+	}
+
+	repl.PrintHighightedMsg(w, "Current record")
+	repl.PrintPlain(w, fmt.Sprintf("Started: %s", openRecord.Date().Format("06-01-02 15:04")))
+	repl.PrintPlain(w, fmt.Sprintf("Elapsed: %s", domain.FormatDuration(openRecord.Hours(h.kern.Now()))))
+
+	info := openRecord.Info()
+
+	if info.Description() != "" {
+		repl.PrintPlain(w, fmt.Sprintf("Note: %s", info.Description()))
+	}
+
+	if info.Project() != "" {
+		repl.PrintPlain(w, fmt.Sprintf("Project: %s", info.Project()))
+	}
+
+	if len(info.Tags()) > 0 {
+		repl.PrintPlain(w, fmt.Sprintf("Tags: %s", strings.Join(info.Tags(), ", ")))
+	}
+}
+
+func (h *Handlers) EditRecord(r *repl.Request, w repl.IO) {
+	var err error
+
+	changes := app.OpenRecordChanges{InfoChanges: parseInfoChanges(r)}
+
+	changes.Start, err = repl.ParseOptionalArg(r, "At", domain.ParseHour)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if changes.Start == nil && changes.InfoChanges == (app.InfoChanges{}) {
+		repl.PrintInfoMsg(w, "Nothing to change, use edit; At; Description; Project; Tags")
+		return
+	}
+
+	openRecord, err := h.kern.EditRecord(r.Ctx(), changes)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("Record started at %s updated!", openRecord.Date().Format("15:04")))
+}
+
+func (h *Handlers) AddTemplate(r *repl.Request, w repl.IO) {
+	name, err := r.Arg("Name")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	description, _ := r.Arg("Description")
+	project, _ := r.Arg("Project")
+
+	hours, err := repl.ParseArg(r, "Duration", domain.ParseAmount)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	err = h.kern.AddTemplate(r.Ctx(), name, description, project, hours)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("Template %s saved!", name))
+}
+
+func (h *Handlers) ListTemplates(r *repl.Request, w repl.IO) {
+	templates, err := h.kern.ListTemplates(r.Ctx())
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	list := make([]string, len(templates))
+	for i, template := range templates {
+		item := template.Name()
+
+		if template.Description() != "" {
+			item = fmt.Sprintf("%s: %s", item, template.Description())
+		}
+
+		if template.Project() != "" {
+			item = fmt.Sprintf("%s [%s]", item, template.Project())
+		}
+
+		if template.HasHours() {
+			item = fmt.Sprintf("%s %s", item, domain.FormatDuration(template.Hours()))
+		}
+
+		list[i] = item
+	}
+
+	repl.PrintHighightedMsg(w, "Templates")
+	repl.PrintPlain(w, domain.SprintList(list))
+}
+
+func (h *Handlers) AddRecordFromTemplate(r *repl.Request, w repl.IO) {
+	name, err := r.Arg("Template")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	phours, err := repl.ParseArg(r, "Hours", domain.ParseAmount)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	hours, err := h.kern.AddRecordFromTemplate(r.Ctx(), name, phours)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%0.2f hours inserted!", hours))
+}
+
+func (h *Handlers) StartRecordFromTemplate(r *repl.Request, w repl.IO) {
+	name, err := r.Arg("Template")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	err = h.kern.StartRecordFromTemplate(r.Ctx(), name)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("Record started from %s!", name))
+}
+
 func (h *Handlers) ChangeDate(r *repl.Request, w repl.IO) {
-	date, err := repl.ParseArg(r, "Date", domain.GetDateFromText)
+	date, err := repl.ParseArg(r, "Date", h.kern.ParseDate)
 	if err != nil {
 		repl.PrintError(w, err)
 		return
@@ -125,8 +305,138 @@ func (h *Handlers) ChangeDate(r *repl.Request, w repl.IO) {
 	repl.PrintInfoMsg(w, "Date change!")
 }
 
+func (h *Handlers) Commit(r *repl.Request, w repl.IO) {
+	amount, err := repl.ParseArg(r, "Amount", domain.ParseAmount)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	committed, pooled, err := h.kern.Commit(r.Ctx(), amount)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if pooled > 0 {
+		repl.PrintInfoMsg(w, fmt.Sprintf("%s hours committed, %s hours sent to pool!", domain.FormatDuration(committed), domain.FormatDuration(pooled)))
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%s hours committed!", domain.FormatDuration(committed)))
+}
+
+func (h *Handlers) SendToPool(r *repl.Request, w repl.IO) {
+	hours, err := h.kern.SendToPool(r.Ctx())
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%s hours saved to pool!", domain.FormatDuration(hours)))
+}
+
+func (h *Handlers) PourPool(r *repl.Request, w repl.IO) {
+	amount, err := repl.ParseArg(r, "Amount", domain.ParseAmount)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	hours, err := h.kern.PourPool(r.Ctx(), amount)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%s hours poured from pool!", domain.FormatDuration(hours)))
+}
+
+func (h *Handlers) ListRecords(r *repl.Request, w repl.IO) {
+	records, err := h.kern.ListRecords(r.Ctx())
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if r.Flag("json") {
+		printJSON(w, export.NewListRecords(records))
+		return
+	}
+
+	overlapping := domain.Overlapping(records)
+
+	list := make([]string, len(records))
+	for i, record := range records {
+		list[i] = fmt.Sprintf("[%s] %s-%s %s %s", record.ShortID(), record.Date().Format("15:04"), record.End().Format("15:04"), domain.FormatDuration(record.Hours()), record.Status())
+
+		if !record.Info().IsEmpty() {
+			list[i] = fmt.Sprintf("%s %s", list[i], record.Info())
+		}
+
+		if overlapping[record.ID()] {
+			list[i] = fmt.Sprintf("%s (overlaps)", list[i])
+		}
+	}
+
+	repl.PrintHighightedMsg(w, "Result")
+	repl.PrintPlain(w, domain.SprintList(list))
+}
+
 func (h *Handlers) DeleteStoredRecord(r *repl.Request, w repl.IO) {
+	selector, err := r.Arg("Record")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	force, err := repl.ParseArg(r, "Force", domain.ParseForce)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	record, err := h.kern.DeleteStoredRecord(r.Ctx(), selector, force)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("Record %s deleted!", record.ShortID()))
 }
 
 func (h *Handlers) EditStoredRecord(r *repl.Request, w repl.IO) {
+	selector, err := r.Arg("Record")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	changes := app.RecordChanges{InfoChanges: parseInfoChanges(r)}
+
+	changes.Hours, err = repl.ParseOptionalArg(r, "Hours", domain.ParseDuration)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	changes.Start, err = repl.ParseOptionalArg(r, "At", domain.ParseHour)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	force, err := repl.ParseArg(r, "Force", domain.ParseForce)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	record, err := h.kern.EditStoredRecord(r.Ctx(), selector, changes, force)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("Record %s updated!", record.ShortID()))
 }
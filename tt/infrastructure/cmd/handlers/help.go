@@ -2,25 +2,43 @@ package handlers
 
 func (h *Handlers) AddHelp() {
 	//Records
-	h.mux.AddHelp("add", "Adds a new task record.")
-	h.mux.AddHelp("rec", "Starts a new time recorer.")
+	h.mux.AddHelp("add", "Adds a new task record, optionally followed by description, project and tags (add; 1:30; review; acme; code,pr).")
+	h.mux.AddHelp("rec", "Starts a new time recorer, optionally with description, project and tags (rec; standup; acme).")
 	h.mux.AddHelp("end", "End the current time recorder, base on the initial time calculates the spent time.")
 	h.mux.AddHelp("end at", "Similar to End but you can set the hour when the time recorded ended.")
-	h.mux.AddHelp("commit", "Send all the pending time on current date to the Time Tracker.")
-	h.mux.AddHelp("send pool", "Sends all the pending time to the pool.")
+	h.mux.AddHelp("commit", "Commits the pending time on current date up to an amount (h:mm or hours, empty for all), time over the working time goes to the pool.")
+	h.mux.AddHelp("send pool", "Sends all the pending time on current date to the pool.")
 	h.mux.AddHelp("drop", "Drops the current working time recorder, all the information will be lost.")
-	h.mux.AddHelp("edit", "Allows to modify the current time recorder, keeping the start time of the original record.")
-	h.mux.AddHelp("rec at", "Allows to start a time recorder at an specific hour.")
+	h.mux.AddHelp("edit", "Allows to modify the start time, description, project or tags of the current time recorder, empty values are kept.")
+	h.mux.AddHelp("rec at", "Allows to start a time recorder at an specific hour, optionally with description, project and tags.")
 	h.mux.AddHelp("list", "List all the records on the current date.")
 	h.mux.AddHelp("view", "Allow to view the current time recorder.")
-	h.mux.AddHelp("edit stored", "Allows to edit a non commited recored.")
-	h.mux.AddHelp("pour", "Poures all the time on the pool to the current date.")
-	h.mux.AddHelp("delete", "Allows to delete a non commited recored.")
+	h.mux.AddHelp("edit stored", "Allows to edit the hours, start time, description, project or tags of a record, selected by list number or id prefix. Empty values are kept, commited records need force.")
+	h.mux.AddHelp("pour", "Pours an amount of time from the pool to the current date, empty pours what the date is missing to reach the working time.")
+	h.mux.AddHelp("delete", "Allows to delete a record, selected by list number or id prefix. Commited records need force.")
+
+	//Reports
+	h.mux.AddHelp("report", "Shows the hours per day and project, use week, month (optionally followed by yyyy-mm) or a from and to date.")
+	h.mux.AddHelp("debt", "Shows the hours expected, recorded and committed per day and week since the first record, or between a from and to date (today by default). Days, weeks and totals missing time are highlighted, today only expects the time recorded on it up to the schedule, so it never adds debt but shows overtime.")
+	h.mux.AddHelp("export", "Exports the records as csv, json (lines) or ics to a file, or printed with -, optionally followed by a from and to date (current date by default).")
+	h.mux.AddHelp("import", "Imports records from a csv or json lines file (tt exports, spreadsheets, Toggl or Clockify reports), shows a summary before writing.")
+
+	//Days off
+	h.mux.AddHelp("day off", "Marks a date or a from..to range as days off (holiday, vacation, sick day) with an optional reason, nothing is expected on them (day off; 26-12-24..26-12-31; Vacation).")
+	h.mux.AddHelp("day on", "Removes the days off of a date or a from..to range.")
+	h.mux.AddHelp("days off", "Lists the days off of the current year, or of a date or from..to range, including the dates of the holidays setting.")
+	h.mux.AddHelp("import days off", "Imports the events of an .ics calendar, like a public holidays one, as days off. Yearly events are repeated up to the end of next year.")
 
 	//Navigate
 	h.mux.AddHelp("change date", "Allow to change the current working date.")
 
 	//Templates
-	h.mux.AddHelp("temp add", "Adds a new record template.")
+	h.mux.AddHelp("temp add", "Adds a new record template with a description, project and default duration (optional).")
 	h.mux.AddHelp("temp list", "List all the existing templates.")
+	h.mux.AddHelp("add from", "Adds a new task record from a template, empty hours use the template duration.")
+	h.mux.AddHelp("rec from", "Starts a new time recorder from a template.")
+
+	//Config
+	h.mux.AddHelp("config show", "Shows the settings with their values and where they come from (file, default or environment variable).")
+	h.mux.AddHelp("config set", "Changes a setting and saves it on the config file, it applies right away (config set; workingTime; 7:30). A new workingTime is a schedule entry from today.")
 }
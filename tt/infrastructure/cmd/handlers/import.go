@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/importer"
+)
+
+// Maximum number of invalid lines shown after a dry run
+const maxLineErrors = 5
+
+// Time to save the records once the import is confirmed
+const importTimeout = time.Minute
+
+func (h *Handlers) Import(r *repl.Request, w repl.IO) {
+	path, err := r.Arg("File")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	format, _ := r.Arg("Format")
+
+	file, err := os.Open(path)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+	defer file.Close()
+
+	records, lineErrors, err := importer.Read(file, format)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	summary, err := h.kern.ImportRecords(r.Ctx(), records, true)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintHighightedMsg(w, "Import summary")
+	repl.PrintPlain(w, sprintImportSummary(summary, lineErrors))
+
+	if summary.Imported == 0 {
+		repl.PrintInfoMsg(w, "Nothing to import!")
+		return
+	}
+
+	answer, err := w.ReadWithPrompt(fmt.Sprintf("- Import %d records? (y/N): ", summary.Imported))
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		repl.PrintInfoMsg(w, "Import cancelled!")
+		return
+	}
+
+	// The request context may be gone while waiting for the answer
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	summary, err = h.kern.ImportRecords(ctx, records, false)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintInfoMsg(w, fmt.Sprintf("%d records imported!", summary.Imported))
+}
+
+func sprintImportSummary(summary app.ImportSummary, lineErrors []importer.LineError) string {
+	lines := []string{
+		fmt.Sprintf("New records: %d (%s hours)", summary.Imported, domain.FormatDuration(summary.Hours)),
+	}
+
+	if summary.Imported > 0 {
+		lines = append(lines, fmt.Sprintf("Dates: %s to %s", summary.From.Format("06-01-02"), summary.To.Format("06-01-02")))
+	}
+
+	if summary.PoolHours > 0 {
+		lines = append(lines, fmt.Sprintf("To the pool, non working days: %s hours", domain.FormatDuration(summary.PoolHours)))
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("Skipped, existing id: %d", summary.DuplicatedID),
+		fmt.Sprintf("Skipped, same start and duration: %d", summary.DuplicatedTime),
+		fmt.Sprintf("Invalid lines: %d", len(lineErrors)),
+	)
+
+	for i, lineError := range lineErrors {
+		if i == maxLineErrors {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(lineErrors)-maxLineErrors))
+			break
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s", lineError))
+	}
+
+	return strings.Join(lines, "\n")
+}
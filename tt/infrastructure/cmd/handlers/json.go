@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"encoding/json"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+)
+
+// Commands that support it print json instead of text when the request has
+// the json flag, used by the one-shot cli for scripting
+func printJSON(w repl.IO, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	repl.PrintPlain(w, string(data))
+}
@@ -4,16 +4,50 @@ import (
 	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
 )
 
+// Optional args describing a record
+var infoArgs = []string{"Description", "Project", "Tags"}
+
 func (h *Handlers) Register() {
 	//Records
-	h.mux.Handle("add", repl.HandleFunc(h.AddRecord), "Hours")
-	h.mux.Handle("rec", repl.HandleFunc(h.StartRecord))
-	h.mux.Handle("rec at", repl.HandleFunc(h.StartRecordAt), "At")
+	h.mux.HandleWithOptional("add", repl.HandleFunc(h.AddRecord), []string{"Hours"}, infoArgs...)
+	h.mux.HandleWithOptional("rec", repl.HandleFunc(h.StartRecord), nil, infoArgs...)
+	h.mux.HandleWithOptional("rec at", repl.HandleFunc(h.StartRecordAt), []string{"At"}, infoArgs...)
 
 	h.mux.Handle("end", repl.HandleFunc(h.StopRecord))
 	h.mux.Handle("end at", repl.HandleFunc(h.StopRecordAt), "At")
 	h.mux.Handle("drop", repl.HandleFunc(h.DropRecord))
+	h.mux.Handle("view", repl.HandleFunc(h.ViewRecord))
+	h.mux.HandleWithOptional("edit", repl.HandleFunc(h.EditRecord), nil, append([]string{"At"}, infoArgs...)...)
+	h.mux.Handle("list", repl.HandleFunc(h.ListRecords))
+	h.mux.HandleWithOptional("edit stored", repl.HandleFunc(h.EditStoredRecord), []string{"Record", "Hours", "At", "Force"}, infoArgs...)
+	h.mux.Handle("delete", repl.HandleFunc(h.DeleteStoredRecord), "Record", "Force")
+	h.mux.Handle("commit", repl.HandleFunc(h.Commit), "Amount")
+	h.mux.Handle("send pool", repl.HandleFunc(h.SendToPool))
+	h.mux.Handle("pour", repl.HandleFunc(h.PourPool), "Amount")
+
+	//Templates
+	h.mux.Handle("temp add", repl.HandleFunc(h.AddTemplate), "Name", "Description", "Project", "Duration")
+	h.mux.Handle("temp list", repl.HandleFunc(h.ListTemplates))
+	h.mux.Handle("add from", repl.HandleFunc(h.AddRecordFromTemplate), "Template", "Hours")
+	h.mux.Handle("rec from", repl.HandleFunc(h.StartRecordFromTemplate), "Template")
+
+	//Reports
+	h.mux.HandleWithOptional("report", repl.HandleFunc(h.Report), []string{"Period"}, "To")
+	h.mux.HandleWithOptional("debt", repl.HandleFunc(h.Debt), nil, "From", "To")
+
+	h.mux.HandleWithOptional("export", repl.HandleFunc(h.Export), []string{"Format", "File"}, "From", "To")
+	h.mux.HandleWithOptional("import", repl.HandleFunc(h.Import), []string{"File"}, "Format")
+
+	//Days off
+	h.mux.HandleWithOptional("day off", repl.HandleFunc(h.AddDaysOff), []string{"Date"}, "Reason")
+	h.mux.Handle("day on", repl.HandleFunc(h.DeleteDaysOff), "Date")
+	h.mux.HandleWithOptional("days off", repl.HandleFunc(h.ListDaysOff), nil, "Date")
+	h.mux.Handle("import days off", repl.HandleFunc(h.ImportDaysOff), "File")
 
 	//Navigate
 	h.mux.Handle("change date", repl.HandleFunc(h.ChangeDate), "Date")
+
+	//Config
+	h.mux.Handle("config show", repl.HandleFunc(h.ShowConfig))
+	h.mux.Handle("config set", repl.HandleFunc(h.SetConfig), "Key", "Value")
 }
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/export"
+)
+
+func (h *Handlers) Report(r *repl.Request, w repl.IO) {
+	period, err := r.Arg("Period")
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	value, _ := r.Arg("To")
+
+	var report *domain.Report
+
+	switch period {
+	case "week":
+		report, err = h.kern.WeekReport(r.Ctx())
+	case "month":
+		month := h.kern.GetDate()
+		if value != "" {
+			month, err = domain.ParseMonth(value, h.kern.Now())
+			if err != nil {
+				repl.PrintError(w, err)
+				return
+			}
+		}
+
+		report, err = h.kern.MonthReport(r.Ctx(), month)
+	default:
+		var from time.Time
+
+		from, err = h.kern.ParseDate(period)
+		if err != nil {
+			repl.PrintError(w, err)
+			return
+		}
+
+		to := h.kern.GetDate()
+		if value != "" {
+			to, err = h.kern.ParseDate(value)
+			if err != nil {
+				repl.PrintError(w, err)
+				return
+			}
+		}
+
+		report, err = h.kern.Report(r.Ctx(), from, to)
+	}
+
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if r.Flag("json") {
+		printJSON(w, export.NewReport(report))
+		return
+	}
+
+	repl.PrintHighightedMsg(w, fmt.Sprintf("Report %s to %s", report.From.Format("06-01-02"), report.To.Format("06-01-02")))
+	repl.PrintPlain(w, sprintReport(report))
+}
+
+func (h *Handlers) Debt(r *repl.Request, w repl.IO) {
+	from, err := repl.ParseOptionalArg(r, "From", h.kern.ParseDate)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	to, err := repl.ParseOptionalArg(r, "To", h.kern.ParseDate)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	// Zero dates leave the range open to the first record and today
+	var start, end time.Time
+	if from != nil {
+		start = *from
+	}
+
+	if to != nil {
+		end = *to
+	}
+
+	debt, err := h.kern.Debt(r.Ctx(), start, end)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
+
+	if r.Flag("json") {
+		printJSON(w, export.NewDebt(debt))
+		return
+	}
+
+	if len(debt.Days) == 0 {
+		repl.PrintInfoMsg(w, "There are no records yet")
+		return
+	}
+
+	repl.PrintHighightedMsg(w, fmt.Sprintf("Debt %s to %s", debt.From.Format("06-01-02"), debt.To.Format("06-01-02")))
+
+	lines, gaps := sprintDebt(debt)
+	for i, line := range lines {
+		if gaps[i] {
+			repl.PrintHighightedMsg(w, line)
+			continue
+		}
+
+		repl.PrintPlain(w, line)
+	}
+}
+
+// Table of the days with their week and range totals, days without expected
+// or recorded time are left out. Rows missing time are flagged on gaps, like
+// on the json output.
+func sprintDebt(debt *domain.Debt) ([]string, []bool) {
+	buf := bytes.NewBufferString("")
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	var gaps []bool
+
+	row := func(label string, day domain.DayBalance) {
+		gap := day.HasGap()
+
+		missing := ""
+		if gap {
+			missing = domain.FormatDuration(day.Debt())
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t\n", label,
+			domain.FormatDuration(day.Expected),
+			domain.FormatDuration(day.Recorded),
+			domain.FormatDuration(day.Committed),
+			domain.FormatSignedDuration(day.Debt()),
+			missing)
+		gaps = append(gaps, gap)
+	}
+
+	fmt.Fprintln(tw, "Date\tExpected\tRecorded\tCommited\tDebt\tGap\t")
+	gaps = append(gaps, false)
+
+	for _, week := range debt.Weeks() {
+		for _, day := range week.Days {
+			if day.Expected == 0 && day.Recorded == 0 {
+				continue
+			}
+
+			row(day.Date.Format("Mon 06-01-02"), day)
+		}
+
+		row("Week "+week.From.Format("06-01-02"), week.Total())
+	}
+
+	row("Total", debt.Total())
+
+	tw.Flush()
+
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), gaps
+}
+
+func sprintReport(report *domain.Report) string {
+	buf := bytes.NewBufferString("")
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "Date\tExpected\tWorked\tCommited\tPool\tDelta\t")
+
+	row := func(label string, day domain.ReportDay) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t\n", label,
+			domain.FormatDuration(day.Expected),
+			domain.FormatDuration(day.Worked()),
+			domain.FormatDuration(day.Committed),
+			domain.FormatDuration(day.Pool),
+			domain.FormatSignedDuration(day.Delta()))
+	}
+
+	for _, day := range report.Days {
+		row(day.Date.Format("Mon 06-01-02"), day)
+	}
+
+	row("Total", report.Total())
+
+	tw.Flush()
+
+	if len(report.Projects) > 0 {
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "Projects")
+
+		projects := make([]string, len(report.Projects))
+		for i, p := range report.Projects {
+			name := p.Project
+			if name == "" {
+				name = "(no project)"
+			}
+
+			projects[i] = fmt.Sprintf("%s %s", name, domain.FormatDuration(p.Hours))
+		}
+
+		fmt.Fprint(buf, domain.SprintList(projects))
+	}
+
+	return buf.String()
+}
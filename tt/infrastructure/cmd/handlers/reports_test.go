@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/export"
+)
+
+func TestSprintDebtGaps(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, time.October, d, 0, 0, 0, 0, time.Local)
+	}
+
+	summaries := []domain.HoursSummary{
+		{Date: day(8), Status: domain.Committed, Hours: 8},
+		{Date: day(9), Status: domain.Committed, Hours: 10},
+		{Date: day(12), Status: domain.Committed, Hours: 6},
+	}
+
+	// Thursday and Friday even with credit, Monday and Tuesday missing time
+	debt := domain.NewDebt(day(8), day(13), day(20), summaries, domain.NewFlatSchedule(8), nil)
+
+	lines, gaps := sprintDebt(debt)
+	if len(lines) != len(gaps) {
+		t.Fatalf("got %d lines and %d gaps", len(lines), len(gaps))
+	}
+
+	view := export.NewDebt(debt)
+
+	// Header, two days, week, two days, week and total
+	want := []bool{false,
+		view.Days[0].Gap, view.Days[1].Gap, view.Weeks[0].Total.Gap,
+		view.Days[4].Gap, view.Days[5].Gap, view.Weeks[1].Total.Gap,
+		view.Total.Gap}
+
+	if len(gaps) != len(want) {
+		t.Fatalf("got %d rows, want %d:\n%v", len(gaps), len(want), lines)
+	}
+
+	for i := range want {
+		if gaps[i] != want[i] {
+			t.Errorf("row %q has gap %v, the json view %v", lines[i], gaps[i], want[i])
+		}
+	}
+
+	if !gaps[6] || !gaps[7] || gaps[3] {
+		t.Errorf("unexpected gaps %v", gaps)
+	}
+}
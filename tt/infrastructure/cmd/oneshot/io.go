@@ -0,0 +1,72 @@
+package oneshot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+)
+
+// IO for a single command, plain output and input read from a pipe
+type StdIO struct {
+	out    io.Writer
+	errOut io.Writer
+	in     *bufio.Reader
+	failed bool
+}
+
+func NewStdIO(out, errOut io.Writer, in io.Reader) *StdIO {
+	return &StdIO{
+		out:    out,
+		errOut: errOut,
+		in:     bufio.NewReader(in),
+	}
+}
+
+// Reports if an error message was written
+func (s *StdIO) Failed() bool {
+	return s.failed
+}
+
+func (s *StdIO) Write(p string, flags ...repl.Flag) error {
+	if repl.GetFlagValue(flags, "response-type") == repl.ErrorMsgResponse {
+		s.failed = true
+		_, err := fmt.Fprintf(s.errOut, "error: %s\n", p)
+		return err
+	}
+
+	_, err := fmt.Fprintln(s.out, p)
+
+	return err
+}
+
+func (s *StdIO) SetPrompt(string) error {
+	return nil
+}
+
+func (s *StdIO) Read() (string, error) {
+	line, err := s.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("input required, pass all the arguments or pipe the answers")
+	}
+
+	return trimNewLine(line), nil
+}
+
+func (s *StdIO) ReadWithPrompt(prompt string) (string, error) {
+	fmt.Fprint(s.errOut, prompt)
+
+	return s.Read()
+}
+
+func trimNewLine(line string) string {
+	for len(line) > 0 && (line[len(line)-1] == '\n' || line[len(line)-1] == '\r') {
+		line = line[:len(line)-1]
+	}
+
+	return line
+}
@@ -0,0 +1,102 @@
+package oneshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl/mux"
+)
+
+// Options given as --name or --name value before or after the command
+type options struct {
+	date *time.Time
+	json bool
+}
+
+// Runs a single command given on the command line, like tt end at 17:30 or
+// tt list --date -1, and returns the exit code
+func Run(kern *app.App, m *mux.Mux, args []string, out, errOut io.Writer, in io.Reader) int {
+	opts, words, err := parseOptions(args, kern.Now())
+	if err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return 2
+	}
+
+	verb, argVals, ok := m.MatchVerb(words)
+	if !ok {
+		fmt.Fprintf(errOut, "error: command not found: %s, use tt help\n", strings.Join(words, " "))
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if opts.date != nil {
+		err = kern.ChangeDate(ctx, *opts.date)
+		if err != nil {
+			fmt.Fprintf(errOut, "error: %v\n", err)
+			return 1
+		}
+	}
+
+	req := repl.NewRequest(ctx, verb, argVals)
+	if opts.json {
+		req.SetFlag("json")
+	}
+
+	stdio := NewStdIO(out, errOut, in)
+
+	m.ServeCmd(req, stdio)
+
+	if stdio.Failed() {
+		return 1
+	}
+
+	return 0
+}
+
+func parseOptions(args []string, now time.Time) (options, []string, error) {
+	opts := options{}
+	words := []string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "--") {
+			words = append(words, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+
+		switch name {
+		case "json":
+			opts.json = true
+		case "date":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return opts, nil, fmt.Errorf("--date needs a value")
+				}
+
+				i++
+				value = args[i]
+			}
+
+			date, err := domain.GetDateFromText(value, now)
+			if err != nil {
+				return opts, nil, fmt.Errorf("invalid --date, %w", err)
+			}
+
+			opts.date = &date
+		default:
+			return opts, nil, fmt.Errorf("unknown option --%s", name)
+		}
+	}
+
+	return opts, words, nil
+}
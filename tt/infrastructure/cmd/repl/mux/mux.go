@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
 )
@@ -14,8 +15,9 @@ type Mux struct {
 }
 
 type handlerWithArgs struct {
-	handler  repl.Handler
-	argNames []string
+	handler      repl.Handler
+	argNames     []string
+	optionalArgs []string
 }
 
 func NewMux() *Mux {
@@ -33,6 +35,12 @@ func (m *Mux) Handle(verb string, h repl.Handler, args ...string) {
 	m.handlers[verb] = handlerWithArgs{handler: h, argNames: args}
 }
 
+// Same as Handle, but the optional args go after the required ones and are
+// only set when given, they are never prompted
+func (m *Mux) HandleWithOptional(verb string, h repl.Handler, args []string, optionalArgs ...string) {
+	m.handlers[verb] = handlerWithArgs{handler: h, argNames: args, optionalArgs: optionalArgs}
+}
+
 func (m *Mux) AddHelp(verb, help string) error {
 	if _, ok := m.helps[verb]; ok {
 		panic(fmt.Sprintf("help for verb %s already exists", verb))
@@ -70,6 +78,19 @@ func (m *Mux) get(verb string) (handlerWithArgs, bool) {
 	return h, true
 }
 
+// Finds the longest registered verb the words start with, returns it with the
+// remaining words
+func (m *Mux) MatchVerb(words []string) (string, []string, bool) {
+	for n := len(words); n > 0; n-- {
+		verb := strings.Join(words[:n], " ")
+		if _, ok := m.get(verb); ok {
+			return verb, words[n:], true
+		}
+	}
+
+	return "", words, false
+}
+
 func (m *Mux) help(r *repl.Request, w repl.IO) {
 	repl.PrintHighightedMsg(w, "Command list")
 
@@ -85,19 +106,25 @@ func cmdNotFound(r *repl.Request, w repl.IO) {
 	repl.PrintErrorMsg(w, fmt.Sprintf("command not found: %s", r.Verb()))
 }
 
-func (m *Mux) Handler(req *repl.Request) (repl.Handler, []string) {
+func (m *Mux) Handler(req *repl.Request) (repl.Handler, []string, []string) {
 	h, ok := m.get(req.Verb())
 	if !ok {
-		return repl.HandleFunc(cmdNotFound), nil
+		return repl.HandleFunc(cmdNotFound), nil, nil
 	}
 
-	return h.handler, h.argNames
+	return h.handler, h.argNames, h.optionalArgs
 }
 
 func (m *Mux) ServeCmd(r *repl.Request, w repl.IO) {
-	h, argNames := m.Handler(r)
+	h, argNames, optionalArgs := m.Handler(r)
+
+	err := m.handleArgs(r, w, argNames)
+	if err != nil {
+		repl.PrintError(w, err)
+		return
+	}
 
-	m.handleArgs(r, w, argNames)
+	m.handleOptionalArgs(r, len(argNames), optionalArgs)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -107,7 +134,7 @@ func (m *Mux) ServeCmd(r *repl.Request, w repl.IO) {
 	h.ServeCmd(r, w)
 }
 
-func (c *Mux) handleArgs(r *repl.Request, w repl.IO, argNames []string) {
+func (c *Mux) handleArgs(r *repl.Request, w repl.IO, argNames []string) error {
 	argVals := r.ArgVals()
 	remArgs := argNames
 
@@ -128,11 +155,23 @@ func (c *Mux) handleArgs(r *repl.Request, w repl.IO, argNames []string) {
 		for _, argName := range remArgs {
 			val, err := w.ReadWithPrompt(fmt.Sprintf("- %s: ", argName))
 			if err != nil {
-				panic(err)
+				return err
 			}
 
 			r.SetArg(argName, val)
 		}
 
 	}
+
+	return nil
+}
+
+func (c *Mux) handleOptionalArgs(r *repl.Request, offset int, optionalArgs []string) {
+	argVals := r.ArgVals()
+
+	for i, argName := range optionalArgs {
+		if offset+i < len(argVals) {
+			r.SetArg(argName, argVals[offset+i])
+		}
+	}
 }
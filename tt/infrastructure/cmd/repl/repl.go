@@ -69,13 +69,21 @@ func (c *Repl) promptUpdate(forceRefresh bool) {
 	if c.data.Dt() > 0 {
 		statusBar = fmt.Sprintf("[Debt:%s]", domain.FormatDuration(c.data.Dt()))
 	} else if c.data.Dt() < 0 {
-		statusBar = fmt.Sprintf("[Pool:%s]", domain.FormatDuration(-c.data.Dt()))
+		statusBar = fmt.Sprintf("[Debt:-%s]", domain.FormatDuration(-c.data.Dt()))
 	}
 
 	if c.data.Wt() > 0 {
 		statusBar = fmt.Sprintf("%s[Worked:%s]", statusBar, domain.FormatDuration(c.data.Wt()))
 	}
 
+	if c.data.Ct() > 0 {
+		statusBar = fmt.Sprintf("%s[Commited:%s]", statusBar, domain.FormatDuration(c.data.Ct()))
+	}
+
+	if c.data.Pt() > 0 {
+		statusBar = fmt.Sprintf("%s[Pool:%s]", statusBar, domain.FormatDuration(c.data.Pt()))
+	}
+
 	if c.data.IsWorking() {
 		statusBar = fmt.Sprintf("%s[Rec:%s][%s]", statusBar, domain.FormatDuration(c.data.Tt()), getClockEmoji())
 	}
@@ -84,6 +92,10 @@ func (c *Repl) promptUpdate(forceRefresh bool) {
 		statusBar = fmt.Sprintf("%s[%s]", statusBar, c.data.GetDate().Format("06-01-02"))
 	}
 
+	if c.data.IsNonWorkingDay() {
+		statusBar = fmt.Sprintf("%s[Off]", statusBar)
+	}
+
 	if statusBar != "" {
 		statusBar = fmt.Sprintf("%s tt", statusBar)
 	} else {
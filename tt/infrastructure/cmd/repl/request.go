@@ -10,6 +10,7 @@ type Request struct {
 	verb     string
 	argsVals []string
 	args     map[string]string
+	flags    map[string]bool
 }
 
 func NewRequest(ctx context.Context, verb string, argsVals []string) *Request {
@@ -18,6 +19,7 @@ func NewRequest(ctx context.Context, verb string, argsVals []string) *Request {
 		verb:     verb,
 		argsVals: argsVals,
 		args:     map[string]string{},
+		flags:    map[string]bool{},
 	}
 }
 
@@ -50,3 +52,21 @@ func (r *Request) SetNewCtx(ctx context.Context) {
 func (r *Request) SetArg(name, value string) {
 	r.args[name] = value
 }
+
+func (r *Request) SetFlag(name string) {
+	r.flags[name] = true
+}
+
+// Checks a flag given outside of the args, like --json on the command line
+func (r *Request) Flag(name string) bool {
+	return r.flags[name]
+}
+
+func (r *Request) Flags() []string {
+	flags := []string{}
+	for name := range r.flags {
+		flags = append(flags, name)
+	}
+
+	return flags
+}
@@ -1,5 +1,7 @@
 package repl
 
+import "errors"
+
 func ParseArg[T any](r *Request, name string, p func(string) (T, error)) (T, error) {
 	val, err := r.Arg(name)
 
@@ -11,3 +13,22 @@ func ParseArg[T any](r *Request, name string, p func(string) (T, error)) (T, err
 
 	return p(val)
 }
+
+// Parses an argument that can be left empty, returns nil when it is
+func ParseOptionalArg[T any](r *Request, name string, p func(string) (T, error)) (*T, error) {
+	val, err := r.Arg(name)
+	if errors.Is(err, ErrArgNotFound) || val == "" {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	pval, err := p(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pval, nil
+}
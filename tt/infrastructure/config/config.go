@@ -1,59 +1,306 @@
 package config
 
 import (
-	"context"
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
+	"varmijo/time-tracker/tt/domain"
 	"varmijo/time-tracker/tt/infrastructure/utils"
+
+	"github.com/sirupsen/logrus"
 )
 
-type config struct {
-	LogLevel    string  `json:"logLevel"`
-	WorkingTime float64 `json:"workingTime"`
+// Values stored on the config file
+type values struct {
+	LogLevel    string          `json:"logLevel"`
+	WorkingTime float64         `json:"workingTime"`
+	Schedule    []scheduleEntry `json:"schedule"`
+	Holidays    []string        `json:"holidays"`
+}
+
+// Hours from monday to friday, optionally followed by saturday and sunday,
+// from a date on
+type scheduleEntry struct {
+	From  string    `json:"from"`
+	Hours []float64 `json:"hours"`
+}
+
+func (v values) clone() values {
+	v.Schedule = append([]scheduleEntry{}, v.Schedule...)
+	v.Holidays = append([]string{}, v.Holidays...)
+
+	return v
+}
+
+func defaultValues() values {
+	return values{
+		LogLevel:    "error",
+		WorkingTime: 8,
+		Schedule:    []scheduleEntry{},
+		Holidays:    []string{},
+	}
 }
 
 const ConfigFileName = "config.json"
 
-func MustNewConfig() *config {
+type config struct {
+	path  string
+	clock domain.Clock
+	// Values on the file and the ones in use, with the env overrides
+	file      values
+	effective values
+	fileKeys  map[string]bool
+	overrides map[string]string
+	mu        sync.RWMutex
+}
+
+// Loads the config file, creating it with the default values on first run
+func NewConfig(clock domain.Clock) (*config, error) {
 	c := &config{
-		LogLevel: "error",
+		path:     utils.GetConfigPath(ConfigFileName),
+		clock:    clock,
+		file:     defaultValues(),
+		fileKeys: map[string]bool{},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = c.save()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range settings {
+			c.fileKeys[s.key] = true
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("can't read config, %w", err)
+	} else {
+		err = c.decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config file %s, %w", c.path, err)
+		}
+	}
 
-	err := c.load(ctx)
+	err = c.applyEnv()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return c
+	return c, nil
 }
 
-func (s *config) load(_ context.Context) error {
-	configData, err := os.ReadFile(utils.GeAppPath(ConfigFileName))
+func (c *config) decode(data []byte) error {
+	present := map[string]json.RawMessage{}
 
+	err := json.Unmarshal(data, &present)
 	if err != nil {
 		return err
 	}
 
-	c := config{}
-	err = json.Unmarshal(configData, &c)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
 
+	err = dec.Decode(&c.file)
 	if err != nil {
 		return err
 	}
 
-	*s = c
+	for _, s := range settings {
+		if _, ok := present[s.key]; !ok {
+			continue
+		}
+
+		c.fileKeys[s.key] = true
+
+		// The values are validated by setting them back
+		err = s.set(&c.file, s.get(&c.file))
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.key, err)
+		}
+	}
 
 	return nil
 }
 
-func (s *config) GetWorkTime() float64 {
-	return s.WorkingTime
+// Values in use are the file ones replaced by the env variables set
+func (c *config) applyEnv() error {
+	effective := c.file.clone()
+	overrides := map[string]string{}
+
+	for _, s := range settings {
+		value, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+
+		err := s.set(&effective, value)
+		if err != nil {
+			return fmt.Errorf("invalid %s, %w", s.env, err)
+		}
+
+		overrides[s.key] = s.env
+	}
+
+	c.effective = effective
+	c.overrides = overrides
+
+	return nil
+}
+
+func (c *config) save() error {
+	data, err := json.MarshalIndent(c.file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return fmt.Errorf("can't create config folder, %w", err)
+	}
+
+	// Written aside and renamed so a failure doesn't leave half a file
+	tmp := c.path + ".tmp"
+
+	err = os.WriteFile(tmp, append(data, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("can't save config, %w", err)
+	}
+
+	err = os.Rename(tmp, c.path)
+	if err != nil {
+		return fmt.Errorf("can't save config, %w", err)
+	}
+
+	return nil
 }
 
-func (s *config) GetLogLevel() string {
-	return s.LogLevel
+// Schedule with workingTime from monday to friday, changed from the dates of
+// the schedule entries on
+func (c *config) GetSchedule() domain.Schedule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	changes := make([]domain.WorkWeek, 0, len(c.effective.Schedule))
+
+	for _, entry := range c.effective.Schedule {
+		week, err := entry.toDomain()
+		if err != nil {
+			logrus.Errorf("Invalid schedule entry %+v on config: %v", entry, err)
+			continue
+		}
+
+		changes = append(changes, week)
+	}
+
+	return domain.NewSchedule(domain.NewFlatWorkWeek(time.Time{}, c.effective.WorkingTime), changes)
+}
+
+func (c *config) GetHolidays() []time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	holidays := make([]time.Time, 0, len(c.effective.Holidays))
+
+	for _, sholiday := range c.effective.Holidays {
+		holiday, err := parseHoliday(sholiday)
+		if err != nil {
+			logrus.Errorf("Invalid holiday %q on config: %v", sholiday, err)
+			continue
+		}
+
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays
+}
+
+func (c *config) GetLogLevel() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.effective.LogLevel
+}
+
+func (c *config) GetAll() []domain.ConfigEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]domain.ConfigEntry, len(settings))
+
+	for i, s := range settings {
+		entries[i] = c.entry(s)
+	}
+
+	return entries
+}
+
+func (c *config) entry(s setting) domain.ConfigEntry {
+	source := "default"
+	if env, ok := c.overrides[s.key]; ok {
+		source = env
+	} else if c.fileKeys[s.key] {
+		source = c.path
+	}
+
+	return domain.ConfigEntry{
+		Key:    s.key,
+		Value:  s.get(&c.effective),
+		Source: source,
+	}
+}
+
+// Validates and saves a setting on the file, the change is applied right away
+// unless an env variable overrides it. Returns the setting changed, a new
+// workingTime is a schedule entry from today.
+func (c *config) Set(key, value string) (domain.ConfigEntry, error) {
+	s, ok := findSetting(key)
+	if !ok {
+		return domain.ConfigEntry{}, fmt.Errorf("unknown config key %q, valid keys are %s", key, keys())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file := c.file.clone()
+	changed := s.key
+
+	var err error
+	if s.change != nil {
+		changed, err = s.change(&file, value, c.clock.Now())
+	} else {
+		err = s.set(&file, value)
+	}
+	if err != nil {
+		return domain.ConfigEntry{}, fmt.Errorf("invalid %s, %w", key, err)
+	}
+
+	prev := c.file
+	c.file = file
+
+	err = c.save()
+	if err != nil {
+		c.file = prev
+		return domain.ConfigEntry{}, err
+	}
+
+	c.fileKeys[changed] = true
+
+	err = c.applyEnv()
+	if err != nil {
+		return domain.ConfigEntry{}, err
+	}
+
+	if level, err := logrus.ParseLevel(c.effective.LogLevel); err == nil {
+		logrus.SetLevel(level)
+	}
+
+	s, _ = findSetting(changed)
+
+	return c.entry(s), nil
 }
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, time.October, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestSetWorkingTime(t *testing.T) {
+	// Sunday
+	c := &config{
+		path:     filepath.Join(t.TempDir(), ConfigFileName),
+		clock:    fixedClock(day(18).Add(10 * time.Hour)),
+		file:     defaultValues(),
+		fileKeys: map[string]bool{},
+	}
+
+	err := c.applyEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Set("workingTime", "0")
+	if err == nil {
+		t.Error("working time must be more than 0")
+	}
+
+	for _, value := range []string{"7", "7:30"} {
+		entry, err := c.Set("workingTime", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Key != "schedule" {
+			t.Errorf("got %s changed, want schedule", entry.Key)
+		}
+	}
+
+	schedule := c.GetSchedule()
+
+	if got := schedule.HoursOn(day(16)); got != 8 {
+		t.Errorf("got %v hours on a past day, want the 8 of the base week", got)
+	}
+
+	if got := schedule.HoursOn(day(19)); got != 7.5 {
+		t.Errorf("got %v hours from today on, want 7.5", got)
+	}
+
+	if got := len(c.file.Schedule); got != 1 {
+		t.Errorf("got %d schedule entries, want the one of today", got)
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+const holidayFormat = "2006-01-02"
+
+// Setting that can be shown and changed, set parses and validates the value.
+// config set uses change instead when there is one, it returns the key of the
+// setting it changed.
+type setting struct {
+	key    string
+	env    string
+	get    func(v *values) string
+	set    func(v *values, value string) error
+	change func(v *values, value string, today time.Time) (string, error)
+}
+
+var settings = []setting{
+	{
+		key: "logLevel",
+		env: "TT_LOG_LEVEL",
+		get: func(v *values) string { return v.LogLevel },
+		set: func(v *values, value string) error {
+			_, err := logrus.ParseLevel(value)
+			if err != nil {
+				return fmt.Errorf("%q is not a log level (trace, debug, info, warn, error, fatal, panic)", value)
+			}
+
+			v.LogLevel = value
+
+			return nil
+		},
+	},
+	{
+		key: "workingTime",
+		env: "TT_WORKING_TIME",
+		get: func(v *values) string { return strconv.FormatFloat(v.WorkingTime, 'f', -1, 64) },
+		set: func(v *values, value string) error {
+			hours, err := parseWorkingTime(value)
+			if err != nil {
+				return err
+			}
+
+			v.WorkingTime = hours
+
+			return nil
+		},
+		// The base week is kept, so the debt of past days doesn't change
+		change: func(v *values, value string, today time.Time) (string, error) {
+			hours, err := parseWorkingTime(value)
+			if err != nil {
+				return "", err
+			}
+
+			entry := scheduleEntry{
+				From:  today.Format(holidayFormat),
+				Hours: []float64{hours, hours, hours, hours, hours},
+			}
+
+			v.Schedule = withScheduleEntry(v.Schedule, entry)
+
+			return "schedule", nil
+		},
+	},
+	{
+		key: "schedule",
+		env: "TT_SCHEDULE",
+		get: func(v *values) string { return formatSchedule(v.Schedule) },
+		set: func(v *values, value string) error {
+			schedule, err := parseSchedule(value)
+			if err != nil {
+				return err
+			}
+
+			v.Schedule = schedule
+
+			return nil
+		},
+	},
+	{
+		key: "holidays",
+		env: "TT_HOLIDAYS",
+		get: func(v *values) string { return strings.Join(v.Holidays, ",") },
+		set: func(v *values, value string) error {
+			holidays := []string{}
+
+			for _, sholiday := range domain.ParseTags(value) {
+				_, err := parseHoliday(sholiday)
+				if err != nil {
+					return fmt.Errorf("%q is not a yyyy-mm-dd date", sholiday)
+				}
+
+				holidays = append(holidays, sholiday)
+			}
+
+			v.Holidays = holidays
+
+			return nil
+		},
+	},
+}
+
+func findSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if strings.EqualFold(s.key, key) {
+			return s, true
+		}
+	}
+
+	return setting{}, false
+}
+
+func keys() string {
+	list := make([]string, len(settings))
+	for i, s := range settings {
+		list[i] = s.key
+	}
+
+	return strings.Join(list, ", ")
+}
+
+// Hours as a decimal number or h:mm
+func parseHours(value string) (float64, error) {
+	if strings.Contains(value, ":") {
+		return domain.ParseDuration(value)
+	}
+
+	hours, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number of hours", value)
+	}
+
+	return hours, nil
+}
+
+// Daily hours from monday to friday
+func parseWorkingTime(value string) (float64, error) {
+	hours, err := parseHours(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if hours <= 0 || hours > 24 {
+		return 0, fmt.Errorf("working time must be more than 0 and up to 24 hours")
+	}
+
+	return hours, nil
+}
+
+// Schedule with the entry added, or replacing the one from the same date
+func withScheduleEntry(schedule []scheduleEntry, entry scheduleEntry) []scheduleEntry {
+	changed := append([]scheduleEntry{}, schedule...)
+
+	for i := range changed {
+		if changed[i].From == entry.From {
+			changed[i] = entry
+			return changed
+		}
+	}
+
+	return append(changed, entry)
+}
+
+func parseHoliday(sholiday string) (time.Time, error) {
+	return time.ParseInLocation(holidayFormat, sholiday, time.Local)
+}
+
+func (e scheduleEntry) toDomain() (domain.WorkWeek, error) {
+	from, err := time.ParseInLocation(holidayFormat, e.From, time.Local)
+	if err != nil {
+		return domain.WorkWeek{}, fmt.Errorf("%q is not a yyyy-mm-dd date", e.From)
+	}
+
+	if len(e.Hours) != 5 && len(e.Hours) != 7 {
+		return domain.WorkWeek{}, fmt.Errorf("schedule from %s needs the hours from monday to friday, or to sunday", e.From)
+	}
+
+	hours := [7]float64{}
+	for i, h := range e.Hours {
+		hours[(i+1)%7] = h
+	}
+
+	return domain.NewWorkWeek(from, hours)
+}
+
+// Entries as text, the date followed by the hours of each weekday starting on
+// monday, separated by commas: 2026-11-01 8 8 8 8 6, 2027-01-01 8 8 8 8 8
+func formatSchedule(schedule []scheduleEntry) string {
+	entries := make([]string, len(schedule))
+
+	for i, entry := range schedule {
+		fields := []string{entry.From}
+		for _, h := range entry.Hours {
+			fields = append(fields, strconv.FormatFloat(h, 'f', -1, 64))
+		}
+
+		entries[i] = strings.Join(fields, " ")
+	}
+
+	return strings.Join(entries, ", ")
+}
+
+func parseSchedule(value string) ([]scheduleEntry, error) {
+	schedule := []scheduleEntry{}
+	seen := map[string]bool{}
+
+	for _, sentry := range strings.Split(value, ",") {
+		fields := strings.Fields(sentry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		entry := scheduleEntry{From: fields[0]}
+
+		for _, shours := range fields[1:] {
+			hours, err := parseHours(shours)
+			if err != nil {
+				return nil, err
+			}
+
+			entry.Hours = append(entry.Hours, hours)
+		}
+
+		_, err := entry.toDomain()
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[entry.From] {
+			return nil, fmt.Errorf("schedule has two entries from %s", entry.From)
+		}
+
+		seen[entry.From] = true
+		schedule = append(schedule, entry)
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,222 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/export"
+
+	"github.com/sirupsen/logrus"
+)
+
+const statusRefresh = 5 * time.Second
+
+// Connection to a running daemon, it serves the REPL commands and prompt data
+// from the daemon so every terminal shows the same state
+type Client struct {
+	conn   net.Conn
+	enc    *json.Encoder
+	dec    *json.Decoder
+	connMu sync.Mutex
+	status export.Status
+	sync.RWMutex
+}
+
+var _ domain.PromptData = (*Client)(nil)
+
+// Connects to the daemon, failing if there is none running
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn: conn,
+		enc:  json.NewEncoder(conn),
+		dec:  json.NewDecoder(bufio.NewReader(conn)),
+	}
+
+	err = c.refresh()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go c.keepRefreshing()
+
+	return c, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) ServeCmd(req *repl.Request, w repl.IO) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	err := c.enc.Encode(message{
+		Type:  cmdMsg,
+		Verb:  req.Verb(),
+		Args:  req.ArgVals(),
+		Flags: req.Flags(),
+	})
+	if err != nil {
+		repl.PrintError(w, fmt.Errorf("daemon unreachable, %w", err))
+		return
+	}
+
+	for {
+		var msg message
+
+		err := c.dec.Decode(&msg)
+		if err != nil {
+			repl.PrintError(w, fmt.Errorf("daemon unreachable, %w", err))
+			return
+		}
+
+		switch msg.Type {
+		case writeMsg:
+			err = w.Write(msg.Text, repl.NewFlag("response-type", msg.Kind))
+		case readMsg:
+			err = c.sendInput(w, msg.Text)
+		case doneMsg:
+			return
+		default:
+			err = fmt.Errorf("unexpected daemon message %q", msg.Type)
+		}
+
+		if err != nil {
+			repl.PrintError(w, err)
+			return
+		}
+	}
+}
+
+// Reads the input asked by the daemon, errors are sent so the command stops
+func (c *Client) sendInput(w repl.IO, prompt string) error {
+	var (
+		text string
+		err  error
+	)
+
+	if prompt == "" {
+		text, err = w.Read()
+	} else {
+		text, err = w.ReadWithPrompt(prompt)
+	}
+
+	msg := message{Type: inputMsg, Text: text}
+	if err != nil {
+		msg.Error = err.Error()
+	}
+
+	return c.enc.Encode(msg)
+}
+
+func (c *Client) refresh() error {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	err := c.enc.Encode(message{Type: statusMsg})
+	if err != nil {
+		return err
+	}
+
+	var msg message
+
+	err = c.dec.Decode(&msg)
+	if err != nil {
+		return err
+	}
+
+	if msg.Type != statusMsg || msg.Status == nil {
+		return fmt.Errorf("unexpected daemon message %q", msg.Type)
+	}
+
+	c.Lock()
+	c.status = *msg.Status
+	c.Unlock()
+
+	return nil
+}
+
+// Changes made from other terminals show up without pressing enter
+func (c *Client) keepRefreshing() {
+	for range time.Tick(statusRefresh) {
+		c.RefreshData()
+	}
+}
+
+func (c *Client) RefreshData() {
+	err := c.refresh()
+	if err != nil {
+		logrus.Errorf("can't refresh daemon status: %v", err)
+	}
+}
+
+func (c *Client) Wt() float64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.Worked
+}
+
+func (c *Client) Ct() float64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.Committed
+}
+
+func (c *Client) Pt() float64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.Pool
+}
+
+func (c *Client) Tt() float64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.Tracked
+}
+
+func (c *Client) Dt() float64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.Debt
+}
+
+func (c *Client) IsWorking() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.Working
+}
+
+func (c *Client) IsToday() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.Today
+}
+
+func (c *Client) IsNonWorkingDay() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.status.NonWorkingDay
+}
+
+func (c *Client) GetDate() time.Time {
+	c.RLock()
+	defer c.RUnlock()
+
+	date, err := time.ParseInLocation(time.DateOnly, c.status.Date, time.Local)
+	if err != nil {
+		return time.Now()
+	}
+
+	return date
+}
@@ -0,0 +1,26 @@
+package daemon
+
+import "varmijo/time-tracker/tt/infrastructure/export"
+
+// Messages are sent as json, one per line. A client sends a cmd and the daemon
+// answers with writes and reads until done, or a status request answered with
+// a status.
+const (
+	cmdMsg    = "cmd"
+	writeMsg  = "write"
+	readMsg   = "read"
+	inputMsg  = "input"
+	doneMsg   = "done"
+	statusMsg = "status"
+)
+
+type message struct {
+	Type   string         `json:"type"`
+	Verb   string         `json:"verb,omitempty"`
+	Args   []string       `json:"args,omitempty"`
+	Flags  []string       `json:"flags,omitempty"`
+	Text   string         `json:"text,omitempty"`
+	Kind   string         `json:"kind,omitempty"`
+	Error  string         `json:"error,omitempty"`
+	Status *export.Status `json:"status,omitempty"`
+}
@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+	"varmijo/time-tracker/tt/app"
+	"varmijo/time-tracker/tt/infrastructure/cmd/repl"
+	"varmijo/time-tracker/tt/infrastructure/export"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Owns the app and runs the commands sent by the clients, one at a time. Each
+// client gets an app session, so it has its own current date.
+type Server struct {
+	kern       *app.App
+	newHandler func(*app.App) repl.Handler
+	listener   net.Listener
+}
+
+// Listens on the unix socket, failing if another daemon is using it.
+// newHandler builds the command handler of each client session.
+func Listen(path string, kern *app.App, newHandler func(*app.App) repl.Handler) (*Server, error) {
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("daemon already running on %s", path)
+	}
+
+	// A socket left by a daemon that didn't stop cleanly
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen on %s, %w", path, err)
+	}
+
+	err = os.Chmod(path, 0600)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return &Server{
+		kern:       kern,
+		newHandler: newHandler,
+		listener:   listener,
+	}, nil
+}
+
+// Accepts clients until the server is closed
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	session := s.kern.Session()
+	handler := s.newHandler(session)
+
+	cio := &connIO{
+		enc:  json.NewEncoder(conn),
+		dec:  json.NewDecoder(bufio.NewReader(conn)),
+		lock: session,
+	}
+
+	for {
+		var msg message
+
+		err := cio.dec.Decode(&msg)
+		if err != nil {
+			logrus.Debugf("daemon client gone: %v", err)
+			return
+		}
+
+		switch msg.Type {
+		case cmdMsg:
+			err = serveCmd(session, handler, msg, cio)
+		case statusMsg:
+			err = cio.enc.Encode(message{Type: statusMsg, Status: status(session)})
+		default:
+			err = fmt.Errorf("unknown message %q", msg.Type)
+		}
+
+		if err != nil {
+			logrus.Errorf("daemon client error: %v", err)
+			return
+		}
+	}
+}
+
+// Commands run one at a time as the db is shared by all the clients, the
+// lock is released while waiting for the input of the client
+func serveCmd(session *app.App, handler repl.Handler, msg message, cio *connIO) error {
+	session.Lock()
+	defer session.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req := repl.NewRequest(ctx, msg.Verb, msg.Args)
+	for _, flag := range msg.Flags {
+		req.SetFlag(flag)
+	}
+
+	handler.ServeCmd(req, cio)
+
+	return cio.enc.Encode(message{Type: doneMsg})
+}
+
+func status(session *app.App) *export.Status {
+	session.Lock()
+	defer session.Unlock()
+
+	data := session.GetPromptData()
+	data.RefreshData()
+
+	status := export.NewStatus(data)
+
+	return &status
+}
+
+// Sends the output of a command to the client and asks it for the input
+type connIO struct {
+	enc  *json.Encoder
+	dec  *json.Decoder
+	lock sync.Locker
+}
+
+func (c *connIO) Write(text string, flags ...repl.Flag) error {
+	return c.enc.Encode(message{
+		Type: writeMsg,
+		Text: text,
+		Kind: repl.GetFlagValue(flags, "response-type"),
+	})
+}
+
+func (c *connIO) SetPrompt(string) error {
+	return nil
+}
+
+func (c *connIO) Read() (string, error) {
+	return c.ReadWithPrompt("")
+}
+
+// Reads run while the command holds the lock, the other clients can go on
+// while the user types
+func (c *connIO) ReadWithPrompt(prompt string) (string, error) {
+	c.lock.Unlock()
+	defer c.lock.Lock()
+
+	err := c.enc.Encode(message{Type: readMsg, Text: prompt})
+	if err != nil {
+		return "", err
+	}
+
+	var msg message
+
+	err = c.dec.Decode(&msg)
+	if err != nil {
+		return "", err
+	}
+
+	if msg.Type != inputMsg {
+		return "", fmt.Errorf("expected input, got %q", msg.Type)
+	}
+
+	if msg.Error != "" {
+		return "", errors.New(msg.Error)
+	}
+
+	return msg.Text, nil
+}
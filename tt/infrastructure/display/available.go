@@ -0,0 +1,17 @@
+package display
+
+import (
+	"os"
+	"runtime"
+)
+
+// Checks if there is a desktop where the system tray can be shown, on unix
+// systems other than macOS it needs an X11 or Wayland display
+func Available() bool {
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		return true
+	}
+
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
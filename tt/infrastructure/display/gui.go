@@ -83,13 +83,21 @@ func (g *GUI) tooltipText() string {
 	if g.propmptData.Dt() > 0 {
 		statusBar = fmt.Sprintf("💳 %s", domain.FormatDuration(g.propmptData.Dt()))
 	} else if g.propmptData.Dt() < 0 {
-		statusBar = fmt.Sprintf("🪣 %s", domain.FormatDuration(-g.propmptData.Dt()))
+		statusBar = fmt.Sprintf("💳 -%s", domain.FormatDuration(-g.propmptData.Dt()))
 	}
 
 	if g.propmptData.Wt() > 0 {
 		statusBar = fmt.Sprintf("%s\n🔨 %s", statusBar, domain.FormatDuration(g.propmptData.Wt()))
 	}
 
+	if g.propmptData.Ct() > 0 {
+		statusBar = fmt.Sprintf("%s\n✅ %s", statusBar, domain.FormatDuration(g.propmptData.Ct()))
+	}
+
+	if g.propmptData.Pt() > 0 {
+		statusBar = fmt.Sprintf("%s\n🪣 %s", statusBar, domain.FormatDuration(g.propmptData.Pt()))
+	}
+
 	if !g.propmptData.IsToday() {
 		statusBar = fmt.Sprintf("%s\n📅 %s", statusBar, g.propmptData.GetDate().Format("02/Jan/06"))
 	}
@@ -102,7 +110,7 @@ func (g *GUI) getFocusTime() float64 {
 		return 0
 	}
 
-	left := time.Until(*g.focusTimer).Minutes()
+	left := g.focusTimer.Sub(g.app.Now()).Minutes()
 
 	if left < 0 {
 		return 0
@@ -115,7 +123,7 @@ func (g *GUI) getPomodoroTime() float64 {
 	if g.pomodoroTimer == nil {
 		return 0
 	}
-	left := time.Until(*g.pomodoroTimer).Minutes()
+	left := g.pomodoroTimer.Sub(g.app.Now()).Minutes()
 
 	if left < 0 {
 		return 0
@@ -176,8 +184,9 @@ func (g *GUI) clearTimers(from, to runningStatus) error {
 }
 
 func (g *GUI) startTimers(_, _ runningStatus) error {
-	focusTime := time.Now().Add(2 * time.Minute)
-	pomodoroTime := time.Now().Add(25 * time.Minute)
+	now := g.app.Now()
+	focusTime := now.Add(2 * time.Minute)
+	pomodoroTime := now.Add(25 * time.Minute)
 	g.focusTimer = &focusTime
 	g.pomodoroTimer = &pomodoroTime
 
@@ -291,12 +300,18 @@ func (g *GUI) onExit() {
 
 func (g *GUI) stardRecord() {
 	if g.getCurrentStatus() == Idle {
-		_ = g.app.StartRecord(context.Background())
+		g.app.Lock()
+		defer g.app.Unlock()
+
+		_ = g.app.StartRecord(context.Background(), domain.RecordInfo{})
 	}
 }
 
 func (g *GUI) stopRecord() {
 	if g.getCurrentStatus() != Idle {
+		g.app.Lock()
+		defer g.app.Unlock()
+
 		_, _ = g.app.StopRecord(context.Background())
 	}
 }
@@ -0,0 +1,209 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"varmijo/time-tracker/tt/domain"
+)
+
+const (
+	CSV       = "csv"
+	JSONLines = "json"
+	ICalendar = "ics"
+)
+
+var Formats = []string{CSV, JSONLines, ICalendar}
+
+// Columns of the csv export, new columns are only appended to keep it stable
+var Columns = []string{"id", "start", "end", "hours", "status", "project", "description", "tags"}
+
+// Exported record, same fields as the csv columns
+type Record struct {
+	ID          string   `json:"id"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Hours       float64  `json:"hours"`
+	Status      string   `json:"status"`
+	Project     string   `json:"project"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+func NewRecord(record *domain.Record) Record {
+	tags := record.Info().Tags()
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return Record{
+		ID:          record.ID(),
+		Start:       record.Date().Format(time.RFC3339),
+		End:         record.End().Format(time.RFC3339),
+		Hours:       record.Hours(),
+		Status:      record.Status().String(),
+		Project:     record.Info().Project(),
+		Description: record.Info().Description(),
+		Tags:        tags,
+	}
+}
+
+func (r Record) row() []string {
+	return []string{
+		r.ID,
+		r.Start,
+		r.End,
+		strconv.FormatFloat(r.Hours, 'f', 4, 64),
+		r.Status,
+		r.Project,
+		r.Description,
+		strings.Join(r.Tags, ","),
+	}
+}
+
+// Checks the format is one of the export formats
+func CheckFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown export format %q, use one of %s", format, strings.Join(Formats, ", "))
+}
+
+// Writes the records on the given format, now is the time of the export
+func Write(w io.Writer, format string, records []*domain.Record, now time.Time) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, records)
+	case JSONLines:
+		return writeJSONLines(w, records)
+	case ICalendar:
+		return writeICalendar(w, records, now)
+	}
+
+	return CheckFormat(format)
+}
+
+func writeCSV(w io.Writer, records []*domain.Record) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(Columns)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		err = cw.Write(NewRecord(record).row())
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func writeJSONLines(w io.Writer, records []*domain.Record) error {
+	enc := json.NewEncoder(w)
+
+	for _, record := range records {
+		err := enc.Encode(NewRecord(record))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const icsTimeFormat = "20060102T150405Z"
+
+func writeICalendar(w io.Writer, records []*domain.Record, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//varmijo//Time Tracker CLI//EN",
+		"CALSCALE:GREGORIAN",
+	}
+
+	stamp := now.UTC().Format(icsTimeFormat)
+
+	for _, record := range records {
+		info := record.Info()
+
+		summary := info.Description()
+		if summary == "" {
+			summary = info.Project()
+		}
+		if summary == "" {
+			summary = "Work"
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s@tt", record.ID()),
+			fmt.Sprintf("DTSTAMP:%s", stamp),
+			fmt.Sprintf("DTSTART:%s", record.Date().UTC().Format(icsTimeFormat)),
+			fmt.Sprintf("DTEND:%s", record.End().UTC().Format(icsTimeFormat)),
+			fmt.Sprintf("SUMMARY:%s", escapeICS(summary)),
+			fmt.Sprintf("DESCRIPTION:%s", escapeICS(fmt.Sprintf("Status: %s\nProject: %s", record.Status(), info.Project()))),
+		)
+
+		if len(info.Tags()) > 0 {
+			tags := make([]string, len(info.Tags()))
+			for i, tag := range info.Tags() {
+				tags[i] = escapeICS(tag)
+			}
+
+			lines = append(lines, fmt.Sprintf("CATEGORIES:%s", strings.Join(tags, ",")))
+		}
+
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for i, line := range lines {
+		lines[i] = foldICS(line)
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+
+	return err
+}
+
+// Maximum octets of a calendar line, without the line break
+const icsLineOctets = 75
+
+// Splits a long line on lines of up to 75 octets, the next ones start with a
+// space. Characters aren't split.
+func foldICS(line string) string {
+	var folded strings.Builder
+
+	size := 0
+	for _, r := range line {
+		octets := utf8.RuneLen(r)
+
+		if size+octets > icsLineOctets {
+			folded.WriteString("\r\n ")
+			size = 1
+		}
+
+		folded.WriteRune(r)
+		size += octets
+	}
+
+	return folded.String()
+}
+
+func escapeICS(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
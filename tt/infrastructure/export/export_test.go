@@ -0,0 +1,54 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+)
+
+func TestWriteICalendar(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+	description := strings.TrimSpace(strings.Repeat("Reviewing the ñandú migration ", 6))
+
+	record, err := domain.NewCloseRecord(now.Add(-2*time.Hour), 2, domain.NewRecordInfo(description, "acme", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+
+	err = Write(&out, ICalendar, []*domain.Record{record}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n")
+	for _, line := range lines {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out.String(), "\r\n ", "")
+
+	if !strings.Contains(unfolded, "\r\nDTSTAMP:20261014T120000Z\r\n") {
+		t.Errorf("missing the stamp of the export time:\n%s", out.String())
+	}
+
+	if !strings.Contains(unfolded, "\r\nSUMMARY:"+description+"\r\n") {
+		t.Errorf("summary is not kept once unfolded:\n%s", unfolded)
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	for _, format := range Formats {
+		if err := CheckFormat(format); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+	}
+
+	if CheckFormat("xlsx") == nil {
+		t.Error("xlsx must be an unknown format")
+	}
+}
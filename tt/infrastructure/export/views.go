@@ -0,0 +1,188 @@
+package export
+
+import (
+	"time"
+	"varmijo/time-tracker/tt/domain"
+)
+
+// Record as shown on a list, flagging the ones overlapping others
+type ListRecord struct {
+	Record
+	Overlaps bool `json:"overlaps"`
+}
+
+func NewListRecords(records []*domain.Record) []ListRecord {
+	overlapping := domain.Overlapping(records)
+
+	list := make([]ListRecord, len(records))
+	for i, record := range records {
+		list[i] = ListRecord{
+			Record:   NewRecord(record),
+			Overlaps: overlapping[record.ID()],
+		}
+	}
+
+	return list
+}
+
+type ReportDay struct {
+	Date      string  `json:"date,omitempty"`
+	Expected  float64 `json:"expected"`
+	Worked    float64 `json:"worked"`
+	Pending   float64 `json:"pending"`
+	Committed float64 `json:"committed"`
+	Pool      float64 `json:"pool"`
+	Delta     float64 `json:"delta"`
+}
+
+type ProjectHours struct {
+	Project string  `json:"project"`
+	Hours   float64 `json:"hours"`
+}
+
+type Report struct {
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Days     []ReportDay    `json:"days"`
+	Total    ReportDay      `json:"total"`
+	Projects []ProjectHours `json:"projects"`
+}
+
+func newReportDay(date string, day domain.ReportDay) ReportDay {
+	return ReportDay{
+		Date:      date,
+		Expected:  day.Expected,
+		Worked:    day.Worked(),
+		Pending:   day.Pending,
+		Committed: day.Committed,
+		Pool:      day.Pool,
+		Delta:     day.Delta(),
+	}
+}
+
+func NewReport(report *domain.Report) Report {
+	out := Report{
+		From:     report.From.Format(time.DateOnly),
+		To:       report.To.Format(time.DateOnly),
+		Days:     make([]ReportDay, len(report.Days)),
+		Total:    newReportDay("", report.Total()),
+		Projects: make([]ProjectHours, len(report.Projects)),
+	}
+
+	for i, day := range report.Days {
+		out.Days[i] = newReportDay(day.Date.Format(time.DateOnly), day)
+	}
+
+	for i, project := range report.Projects {
+		out.Projects[i] = ProjectHours{Project: project.Project, Hours: project.Hours}
+	}
+
+	return out
+}
+
+type DayBalance struct {
+	Date      string  `json:"date,omitempty"`
+	Expected  float64 `json:"expected"`
+	Recorded  float64 `json:"recorded"`
+	Committed float64 `json:"committed"`
+	Debt      float64 `json:"debt"`
+	Gap       bool    `json:"gap"`
+}
+
+type DebtWeek struct {
+	From  string     `json:"from"`
+	Total DayBalance `json:"total"`
+}
+
+type Debt struct {
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Days  []DayBalance `json:"days"`
+	Weeks []DebtWeek   `json:"weeks"`
+	Total DayBalance   `json:"total"`
+}
+
+func newDayBalance(date string, day domain.DayBalance) DayBalance {
+	return DayBalance{
+		Date:      date,
+		Expected:  day.Expected,
+		Recorded:  day.Recorded,
+		Committed: day.Committed,
+		Debt:      day.Debt(),
+		Gap:       day.HasGap(),
+	}
+}
+
+func NewDebt(debt *domain.Debt) Debt {
+	weeks := debt.Weeks()
+
+	out := Debt{
+		From:  debt.From.Format(time.DateOnly),
+		To:    debt.To.Format(time.DateOnly),
+		Days:  make([]DayBalance, len(debt.Days)),
+		Weeks: make([]DebtWeek, len(weeks)),
+		Total: newDayBalance("", debt.Total()),
+	}
+
+	for i, day := range debt.Days {
+		out.Days[i] = newDayBalance(day.Date.Format(time.DateOnly), day)
+	}
+
+	for i, week := range weeks {
+		out.Weeks[i] = DebtWeek{From: week.From.Format(time.DateOnly), Total: newDayBalance("", week.Total())}
+	}
+
+	return out
+}
+
+// Record being tracked
+type OpenRecord struct {
+	Start       string   `json:"start"`
+	Hours       float64  `json:"hours"`
+	Project     string   `json:"project"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// Record being tracked with the hours elapsed until now
+func NewOpenRecord(record *domain.OpenRecord, now time.Time) OpenRecord {
+	tags := record.Info().Tags()
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return OpenRecord{
+		Start:       record.Date().Format(time.RFC3339),
+		Hours:       record.Hours(now),
+		Project:     record.Info().Project(),
+		Description: record.Info().Description(),
+		Tags:        tags,
+	}
+}
+
+// Status bar data
+type Status struct {
+	Date          string  `json:"date"`
+	Today         bool    `json:"today"`
+	NonWorkingDay bool    `json:"non_working_day"`
+	Working       bool    `json:"working"`
+	Tracked       float64 `json:"tracked"`
+	Worked        float64 `json:"worked"`
+	Committed     float64 `json:"committed"`
+	Pool          float64 `json:"pool"`
+	Debt          float64 `json:"debt"`
+}
+
+func NewStatus(data domain.PromptData) Status {
+	return Status{
+		Date:          data.GetDate().Format(time.DateOnly),
+		Today:         data.IsToday(),
+		NonWorkingDay: data.IsNonWorkingDay(),
+		Working:       data.IsWorking(),
+		Tracked:       data.Tt(),
+		Worked:        data.Wt(),
+		Committed:     data.Ct(),
+		Pool:          data.Pt(),
+		Debt:          data.Dt(),
+	}
+}
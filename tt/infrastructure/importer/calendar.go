@@ -0,0 +1,270 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+)
+
+// Reads the days off of an icalendar file, like the public holidays
+// calendars. Every day of each event is a day off with the event summary as
+// reason. Yearly events are repeated until their last occurrence, or until
+// the given date when they don't have one, other recurrences are reported as
+// errors.
+func ReadDaysOff(r io.Reader, until time.Time) ([]*domain.DayOff, []LineError, error) {
+	lines, numbers, err := unfoldLines(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	days := []*domain.DayOff{}
+	lineErrors := []LineError{}
+
+	var (
+		inEvent   bool
+		event     calendarEvent
+		startLine int
+	)
+
+	for i, line := range lines {
+		name, params, value, ok := splitProperty(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			event = calendarEvent{}
+			startLine = numbers[i]
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+
+			eventDays, err := event.daysOff(until)
+			if err != nil {
+				lineErrors = append(lineErrors, LineError{Line: startLine, Err: err})
+				continue
+			}
+
+			days = append(days, eventDays...)
+		case !inEvent:
+		case name == "DTSTART":
+			event.start, event.startTZ = value, params["TZID"]
+		case name == "DTEND":
+			event.end, event.endTZ = value, params["TZID"]
+		case name == "RRULE":
+			event.rrule = value
+		case name == "SUMMARY":
+			event.summary = unescapeText(value)
+		}
+	}
+
+	if len(days) == 0 && len(lineErrors) == 0 {
+		return nil, nil, fmt.Errorf("no events found on the calendar")
+	}
+
+	return days, lineErrors, nil
+}
+
+// Joins the lines folded by starting them with a space or a tab, returns
+// also the number of the file line where each one starts
+func unfoldLines(r io.Reader) ([]string, []int, error) {
+	lines := []string{}
+	numbers := []int{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+		numbers = append(numbers, n)
+	}
+
+	return lines, numbers, scanner.Err()
+}
+
+// Splits NAME;PARAM=X:VALUE into the name, the params and the value
+func splitProperty(line string) (string, map[string]string, string, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(head, ";")
+
+	params := map[string]string{}
+	for _, param := range parts[1:] {
+		key, pvalue, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(pvalue, `"`)
+	}
+
+	return strings.ToUpper(strings.TrimPrefix(parts[0], "\ufeff")), params, strings.TrimSpace(value), true
+}
+
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+type calendarEvent struct {
+	start, startTZ string
+	end, endTZ     string
+	rrule          string
+	summary        string
+}
+
+// Days of the event and of its repetitions
+func (e calendarEvent) daysOff(until time.Time) ([]*domain.DayOff, error) {
+	from, to, err := e.dayRange()
+	if err != nil {
+		return nil, err
+	}
+
+	years, err := yearlyRepetitions(e.rrule, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []*domain.DayOff{}
+	for _, year := range years {
+		for date := from.AddDate(year, 0, 0); date.Before(to.AddDate(year, 0, 0)); date = date.AddDate(0, 0, 1) {
+			days = append(days, domain.NewDayOff(date, e.summary))
+		}
+	}
+
+	return days, nil
+}
+
+// First day of the event and the day after its last one. The end date of all
+// day events is not included, the one of timed events is if it doesn't end at
+// midnight.
+func (e calendarEvent) dayRange() (time.Time, time.Time, error) {
+	start, err := parseCalendarTime(e.start, e.startTZ)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid event start, %w", err)
+	}
+
+	from := domain.StartOfDay(start)
+	to := from.AddDate(0, 0, 1)
+
+	if e.end != "" {
+		end, err := parseCalendarTime(e.end, e.endTZ)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid event end, %w", err)
+		}
+
+		endDay := domain.StartOfDay(end)
+		if !end.Equal(endDay) {
+			endDay = endDay.AddDate(0, 0, 1)
+		}
+
+		if endDay.After(to) {
+			to = endDay
+		}
+	}
+
+	return from, to, nil
+}
+
+// Years from the first date with a repetition of a yearly rule, like
+// FREQ=YEARLY;INTERVAL=1;COUNT=5. Without a count or an until date the
+// repetitions go up to the given date. An empty rule is just the first date.
+func yearlyRepetitions(rrule string, from, until time.Time) ([]int, error) {
+	if rrule == "" {
+		return []int{0}, nil
+	}
+
+	interval, count := 1, 0
+	last := until
+
+	for _, part := range strings.Split(rrule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+
+		var err error
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			if !strings.EqualFold(value, "YEARLY") {
+				return nil, fmt.Errorf("only yearly recurring events are supported, got %s", value)
+			}
+		case "INTERVAL":
+			interval, err = strconv.Atoi(value)
+			if err == nil && interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			count, err = strconv.Atoi(value)
+		case "UNTIL":
+			last, err = parseCalendarTime(value, "")
+		case "BYMONTH", "BYMONTHDAY", "WKST":
+			// Only the month and day of the first date are supported, so
+			// these repeat them
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule %s", part)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence rule %s, %w", part, err)
+		}
+	}
+
+	// The first date is always an occurrence
+	years := []int{0}
+	for year := interval; !from.AddDate(year, 0, 0).After(last); year += interval {
+		if count > 0 && len(years) >= count {
+			break
+		}
+
+		years = append(years, year)
+	}
+
+	return years, nil
+}
+
+// Instant of a yyyymmdd date or a yyyymmddThhmmss date time. Date times
+// ending in Z are UTC and the others are on the tzid time zone, or local if
+// there isn't one. Both are returned on the local time zone.
+func parseCalendarTime(value, tzid string) (time.Time, error) {
+	if len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not a date", value)
+		}
+
+		return t, nil
+	}
+
+	if utc, ok := strings.CutSuffix(value, "Z"); ok {
+		t, err := time.Parse("20060102T150405", utc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not a date", value)
+		}
+
+		return t.Local(), nil
+	}
+
+	location := time.Local
+	if tzid != "" {
+		var err error
+
+		location, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date", value)
+	}
+
+	return t.Local(), nil
+}
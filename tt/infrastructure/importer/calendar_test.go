@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func calendar(events ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0"}
+	for _, event := range events {
+		lines = append(lines, "BEGIN:VEVENT", event, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	return strings.Join(lines, "\r\n")
+}
+
+func TestReadDaysOff(t *testing.T) {
+	// Five hours behind UTC, so late UTC times are on the previous local day
+	local := time.Local
+	time.Local = time.FixedZone("test", -5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	until := time.Date(2028, time.December, 31, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		data   string
+		days   []string
+		reason string
+		errors []int
+	}{
+		{
+			name:   "all day event",
+			data:   calendar("DTSTART;VALUE=DATE:20261225\r\nDTEND;VALUE=DATE:20261226\r\nSUMMARY:Christmas\\, Day"),
+			days:   []string{"2026-12-25"},
+			reason: "Christmas, Day",
+		},
+		{
+			name: "all day event without end",
+			data: calendar("DTSTART;VALUE=DATE:20261225"),
+			days: []string{"2026-12-25"},
+		},
+		{
+			name: "several days",
+			data: calendar("DTSTART;VALUE=DATE:20261224\r\nDTEND;VALUE=DATE:20261227"),
+			days: []string{"2026-12-24", "2026-12-25", "2026-12-26"},
+		},
+		{
+			name:   "folded summary",
+			data:   calendar("DTSTART:20261225\r\nSUMMARY:Christ\r\n mas"),
+			days:   []string{"2026-12-25"},
+			reason: "Christmas",
+		},
+		{
+			name: "utc time on the previous local day",
+			data: calendar("DTSTART:20261225T020000Z\r\nDTEND:20261225T040000Z"),
+			days: []string{"2026-12-24"},
+		},
+		{
+			name: "utc time ending the next local day",
+			data: calendar("DTSTART:20261224T200000Z\r\nDTEND:20261225T200000Z"),
+			days: []string{"2026-12-24", "2026-12-25"},
+		},
+		{
+			name: "time on a time zone",
+			data: calendar("DTSTART;TZID=Asia/Tokyo:20261225T090000\r\nDTEND;TZID=Asia/Tokyo:20261225T100000"),
+			days: []string{"2026-12-24"},
+		},
+		{
+			name: "floating local time",
+			data: calendar("DTSTART:20261225T230000\r\nDTEND:20261226T000000"),
+			days: []string{"2026-12-25"},
+		},
+		{
+			name: "yearly until the given date",
+			data: calendar("DTSTART;VALUE=DATE:20260101\r\nRRULE:FREQ=YEARLY"),
+			days: []string{"2026-01-01", "2027-01-01", "2028-01-01"},
+		},
+		{
+			name: "yearly with count and interval",
+			data: calendar("DTSTART;VALUE=DATE:20260704\r\nRRULE:FREQ=YEARLY;INTERVAL=2;COUNT=2"),
+			days: []string{"2026-07-04", "2028-07-04"},
+		},
+		{
+			name: "yearly until a date",
+			data: calendar("DTSTART;VALUE=DATE:20260501\r\nRRULE:FREQ=YEARLY;BYMONTH=5;UNTIL=20270501"),
+			days: []string{"2026-05-01", "2027-05-01"},
+		},
+		{
+			name:   "unsupported recurrences are reported",
+			data:   calendar("DTSTART:20261101", "DTSTART:20261126\r\nRRULE:FREQ=YEARLY;BYDAY=4TH", "DTSTART:20261102\r\nRRULE:FREQ=WEEKLY"),
+			days:   []string{"2026-11-01"},
+			errors: []int{6, 10},
+		},
+		{
+			name:   "invalid dates are reported",
+			data:   calendar("DTSTART:20261301", "SUMMARY:No start", "DTSTART:20261225T9"),
+			errors: []int{3, 6, 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, lineErrors, err := ReadDaysOff(strings.NewReader(tt.data), until)
+			if err != nil {
+				t.Fatalf("can't read: %v", err)
+			}
+
+			lines := []int{}
+			for _, lineError := range lineErrors {
+				lines = append(lines, lineError.Line)
+			}
+
+			if len(lines) != len(tt.errors) {
+				t.Fatalf("got errors on lines %v, want %v (%v)", lines, tt.errors, lineErrors)
+			}
+
+			for i := range lines {
+				if lines[i] != tt.errors[i] {
+					t.Errorf("got errors on lines %v, want %v", lines, tt.errors)
+				}
+			}
+
+			got := []string{}
+			for _, day := range days {
+				got = append(got, day.Date().Format(time.DateOnly))
+
+				if day.Reason() != tt.reason {
+					t.Errorf("%s has reason %q, want %q", day.Date().Format(time.DateOnly), day.Reason(), tt.reason)
+				}
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.days, ",") {
+				t.Errorf("got days %v, want %v", got, tt.days)
+			}
+		})
+	}
+}
+
+func TestReadDaysOffWithoutEvents(t *testing.T) {
+	_, _, err := ReadDaysOff(strings.NewReader(calendar()), time.Time{})
+	if err == nil {
+		t.Error("a calendar without events must fail")
+	}
+}
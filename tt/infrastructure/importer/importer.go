@@ -0,0 +1,337 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+	"varmijo/time-tracker/tt/infrastructure/export"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Auto      = "auto"
+	CSV       = "csv"
+	JSONLines = "json"
+)
+
+var Formats = []string{Auto, CSV, JSONLines}
+
+// Error on a single line of the imported file, the line is skipped
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Reads the records of a file on the given format. Auto detects json lines
+// or csv, csv columns are mapped by their header so tt exports, spreadsheets
+// and Toggl or Clockify detailed reports are supported. Records without a
+// status are imported as committed.
+func Read(r io.Reader, format string) ([]*domain.Record, []LineError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if format == Auto || format == "" {
+		format = detectFormat(data)
+	}
+
+	switch format {
+	case CSV:
+		return readCSV(bytes.NewReader(data))
+	case JSONLines:
+		return readJSONLines(bytes.NewReader(data))
+	}
+
+	return nil, nil, fmt.Errorf("unknown import format %q, use one of %s", format, strings.Join(Formats, ", "))
+}
+
+func detectFormat(data []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return JSONLines
+	}
+
+	return CSV
+}
+
+// Header names accepted for every field, first match wins
+var columnAliases = map[string][]string{
+	"id":          {"id"},
+	"start":       {"start", "start datetime"},
+	"end":         {"end", "end datetime"},
+	"startDate":   {"start date", "date"},
+	"startTime":   {"start time"},
+	"endDate":     {"end date"},
+	"endTime":     {"end time"},
+	"duration":    {"hours", "duration (decimal)", "duration", "duration (h)"},
+	"status":      {"status"},
+	"project":     {"project"},
+	"description": {"description", "task"},
+	"tags":        {"tags"},
+}
+
+func readCSV(r io.Reader) ([]*domain.Record, []LineError, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't read csv header, %w", err)
+	}
+
+	columns := mapColumns(header)
+
+	if _, ok := columns["start"]; !ok {
+		if _, ok := columns["startDate"]; !ok {
+			return nil, nil, fmt.Errorf("csv needs a start or start date column")
+		}
+	}
+
+	// Records with only a date are chained one after the other on their day
+	_, hasStart := columns["start"]
+	_, hasStartTime := columns["startTime"]
+	dateOnly := !hasStart && !hasStartTime
+	dayEnds := map[string]time.Time{}
+
+	records := []*domain.Record{}
+	lineErrors := []LineError{}
+
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{line, err})
+			continue
+		}
+
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(row) {
+				return ""
+			}
+
+			return strings.TrimSpace(row[i])
+		}
+
+		record, err := newRecord(get)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{line, err})
+			continue
+		}
+
+		if dateOnly {
+			day := record.Date().Format(time.DateOnly)
+			if end, ok := dayEnds[day]; ok {
+				record.UpdateDate(end)
+			}
+
+			dayEnds[day] = record.End()
+		}
+
+		records = append(records, record)
+	}
+
+	return records, lineErrors, nil
+}
+
+func mapColumns(header []string) map[string]int {
+	positions := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := positions[name]; !ok {
+			positions[name] = i
+		}
+	}
+
+	columns := map[string]int{}
+	for field, aliases := range columnAliases {
+		for _, alias := range aliases {
+			if i, ok := positions[alias]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+
+	return columns
+}
+
+func readJSONLines(r io.Reader) ([]*domain.Record, []LineError, error) {
+	scanner := bufio.NewScanner(r)
+
+	records := []*domain.Record{}
+	lineErrors := []LineError{}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var er export.Record
+
+		err := json.Unmarshal([]byte(text), &er)
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{line, err})
+			continue
+		}
+
+		fields := map[string]string{
+			"id":          er.ID,
+			"start":       er.Start,
+			"end":         er.End,
+			"status":      er.Status,
+			"project":     er.Project,
+			"description": er.Description,
+			"tags":        strings.Join(er.Tags, ","),
+		}
+
+		if er.Hours > 0 {
+			fields["duration"] = strconv.FormatFloat(er.Hours, 'f', -1, 64)
+		}
+
+		record, err := newRecord(func(field string) string { return fields[field] })
+		if err != nil {
+			lineErrors = append(lineErrors, LineError{line, err})
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, lineErrors, scanner.Err()
+}
+
+func newRecord(get func(string) string) (*domain.Record, error) {
+	start, err := parseInstant(get("start"), get("startDate"), get("startTime"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start, %w", err)
+	}
+
+	var end time.Time
+
+	if get("end") != "" || get("endTime") != "" {
+		endDate := get("endDate")
+		if endDate == "" {
+			endDate = get("startDate")
+		}
+
+		end, err = parseInstant(get("end"), endDate, get("endTime"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid end, %w", err)
+		}
+	} else if get("duration") != "" {
+		hours, err := parseHours(get("duration"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration, %w", err)
+		}
+
+		_, err = domain.NewHours(hours)
+		if err != nil {
+			return nil, err
+		}
+
+		end = start.Add(time.Duration(hours * float64(time.Hour)))
+	} else {
+		return nil, fmt.Errorf("an end or a duration is required")
+	}
+
+	id := get("id")
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	status := get("status")
+	if status == "" {
+		status = domain.Committed.String()
+	}
+
+	info := domain.NewRecordInfo(get("description"), get("project"), domain.ParseTags(get("tags")))
+
+	return domain.RecreateRecord(id, start, end, status, info)
+}
+
+var dateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+var dateLayouts = []string{"2006-01-02", "01/02/2006", "02.01.2006"}
+
+var timeLayouts = []string{"15:04:05", "15:04", "03:04:05 PM", "03:04 PM"}
+
+// Parses a full instant or a date and a time on separate columns
+func parseInstant(instant, date, hour string) (time.Time, error) {
+	if instant != "" {
+		for _, layout := range dateTimeLayouts {
+			t, err := time.ParseInLocation(layout, instant, time.Local)
+			if err == nil {
+				return t, nil
+			}
+		}
+
+		return time.Time{}, fmt.Errorf("unknown date format %q", instant)
+	}
+
+	if date == "" {
+		return time.Time{}, fmt.Errorf("date is required")
+	}
+
+	if hour == "" {
+		hour = "00:00"
+	}
+
+	for _, dlayout := range dateLayouts {
+		for _, tlayout := range timeLayouts {
+			t, err := time.ParseInLocation(dlayout+" "+tlayout, date+" "+hour, time.Local)
+			if err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown date format %q %q", date, hour)
+}
+
+// Parses decimal hours or a h:mm[:ss] duration
+func parseHours(sd string) (float64, error) {
+	parts := strings.Split(sd, ":")
+
+	if len(parts) == 1 {
+		return strconv.ParseFloat(strings.ReplaceAll(sd, ",", "."), 64)
+	}
+
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("wrong duration format")
+	}
+
+	hours := 0.0
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("wrong duration format")
+		}
+
+		hours += float64(v) / math.Pow(60, float64(i))
+	}
+
+	return hours, nil
+}
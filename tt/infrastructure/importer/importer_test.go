@@ -0,0 +1,218 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+)
+
+func at(d, hour, min int) time.Time {
+	return time.Date(2026, time.October, d, hour, min, 0, 0, time.Local)
+}
+
+type wantRecord struct {
+	start   time.Time
+	hours   float64
+	status  domain.RecordStatus
+	project string
+	desc    string
+	tags    []string
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   []wantRecord
+		errors []int
+	}{
+		{
+			name: "toggl detailed report",
+			data: "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags\n" +
+				"Ann,ann@mail.com,,acme,,Review,No,2026-10-14,09:00:00,2026-10-14,10:30:00,01:30:00,\"dev, review\"\n",
+			want: []wantRecord{
+				{start: at(14, 9, 0), hours: 1.5, status: domain.Committed, project: "acme", desc: "Review", tags: []string{"dev", "review"}},
+			},
+		},
+		{
+			name: "clockify detailed report",
+			data: "\ufeffProject,Client,Description,Task,User,Email,Tags,Billable,Start Date,Start Time,End Date,End Time,Duration (h),Duration (decimal)\n" +
+				"acme,,Planning,,Ann,ann@mail.com,,Yes,10/14/2026,01:15:00 PM,10/14/2026,02:00:00 PM,00:45:00,0.75\n",
+			want: []wantRecord{
+				{start: at(14, 13, 15), hours: 0.75, status: domain.Committed, project: "acme", desc: "Planning"},
+			},
+		},
+		{
+			name: "spreadsheet days are chained",
+			data: "date,hours,project,description\n" +
+				"2026-10-14,2,acme,Morning\n" +
+				"2026-10-14,\"1,5\",acme,Afternoon\n" +
+				"2026-10-15,0:30,,Call\n",
+			want: []wantRecord{
+				{start: at(14, 0, 0), hours: 2, status: domain.Committed, project: "acme", desc: "Morning"},
+				{start: at(14, 2, 0), hours: 1.5, status: domain.Committed, project: "acme", desc: "Afternoon"},
+				{start: at(15, 0, 0), hours: 0.5, status: domain.Committed, desc: "Call"},
+			},
+		},
+		{
+			name: "tt export keeps the status",
+			data: "id,start,end,status,project,description,tags\n" +
+				"a,2026-10-14T09:00:00,2026-10-14T11:00:00,pending,acme,Work,dev\n" +
+				"b,2026-10-14 12:00,,pool,,,\n",
+			want: []wantRecord{
+				{start: at(14, 9, 0), hours: 2, status: domain.Pending, project: "acme", desc: "Work", tags: []string{"dev"}},
+			},
+			errors: []int{3},
+		},
+		{
+			name: "invalid lines are reported",
+			data: "date,hours\n" +
+				"2026-10-14,1\n" +
+				"tomorrow,1\n" +
+				"2026-10-14,1:2:3:4\n" +
+				"2026-10-14,-1\n",
+			want: []wantRecord{
+				{start: at(14, 0, 0), hours: 1, status: domain.Committed},
+			},
+			errors: []int{3, 4, 5},
+		},
+		{
+			name: "json lines",
+			data: `{"id":"a","start":"2026-10-14T09:00:00","end":"2026-10-14T10:00:00","status":"pool","project":"acme","tags":["dev"]}` + "\n\n" +
+				`{"start":"2026-10-14T11:00:00","hours":0.25}` + "\n" +
+				`{"start":` + "\n",
+			want: []wantRecord{
+				{start: at(14, 9, 0), hours: 1, status: domain.Pool, project: "acme", tags: []string{"dev"}},
+				{start: at(14, 11, 0), hours: 0.25, status: domain.Committed},
+			},
+			errors: []int{4},
+		},
+		{
+			name:   "forced format",
+			format: CSV,
+			data:   "start,hours\n2026-10-14 08:00,1\n",
+			want: []wantRecord{
+				{start: at(14, 8, 0), hours: 1, status: domain.Committed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, lineErrors, err := Read(strings.NewReader(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("can't read: %v", err)
+			}
+
+			lines := []int{}
+			for _, lineError := range lineErrors {
+				lines = append(lines, lineError.Line)
+			}
+
+			if len(lines) != len(tt.errors) {
+				t.Fatalf("got errors on lines %v, want %v (%v)", lines, tt.errors, lineErrors)
+			}
+
+			for i := range lines {
+				if lines[i] != tt.errors[i] {
+					t.Errorf("got errors on lines %v, want %v", lines, tt.errors)
+				}
+			}
+
+			if len(records) != len(tt.want) {
+				t.Fatalf("got %d records, want %d", len(records), len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				record := records[i]
+
+				if !record.Date().Equal(want.start) {
+					t.Errorf("record %d starts at %v, want %v", i, record.Date(), want.start)
+				}
+
+				if record.Hours() != want.hours {
+					t.Errorf("record %d has %v hours, want %v", i, record.Hours(), want.hours)
+				}
+
+				if record.Status() != want.status {
+					t.Errorf("record %d is %v, want %v", i, record.Status(), want.status)
+				}
+
+				info := record.Info()
+				if info.Project() != want.project || info.Description() != want.desc {
+					t.Errorf("record %d has project %q and description %q", i, info.Project(), info.Description())
+				}
+
+				if strings.Join(info.Tags(), ",") != strings.Join(want.tags, ",") {
+					t.Errorf("record %d has tags %v, want %v", i, info.Tags(), want.tags)
+				}
+
+				if record.ID() == "" {
+					t.Errorf("record %d has no id", i)
+				}
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{name: "unknown format", format: "xml", data: "<records/>"},
+		{name: "empty csv", data: ""},
+		{name: "csv without start", data: "project,hours\nacme,1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(strings.NewReader(tt.data), tt.format)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		text  string
+		hours float64
+		err   bool
+	}{
+		{text: "1.5", hours: 1.5},
+		{text: "1,25", hours: 1.25},
+		{text: "2", hours: 2},
+		{text: "1:30", hours: 1.5},
+		{text: "01:30:00", hours: 1.5},
+		{text: "0:00:36", hours: 0.01},
+		{text: "1:2:3:4", err: true},
+		{text: "1:xx", err: true},
+		{text: "abc", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			hours, err := parseHours(tt.text)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected an error, got %v", hours)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if hours != tt.hours {
+				t.Errorf("got %v hours, want %v", hours, tt.hours)
+			}
+		})
+	}
+}
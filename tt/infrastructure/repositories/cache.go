@@ -1,36 +1,125 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
 	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
 )
 
+// Cache of the values read from a db, shared by all the repositories of the
+// db. The values are dropped when the db data version changes, so writes made
+// by other processes or directly on the file are seen. The version is read at
+// most once per versionTTL, writes of other processes can take that long to
+// be seen.
 type dbCache struct {
 	records map[string]any
+	version int64
+	checked time.Time
+	conn    *sql.Conn
 	mu      *sync.RWMutex
 }
 
-var cache *dbCache
-var once sync.Once
+const versionTTL = 200 * time.Millisecond
 
-func newDBCache() *dbCache {
-	once.Do(func() {
-		if cache != nil {
-			return
-		}
+var caches = map[*sqlx.DB]*dbCache{}
+var cachesMu sync.Mutex
 
-		c := &dbCache{
-			records: make(map[string]any),
-			mu:      &sync.RWMutex{},
-		}
+func newDBCache(db *sqlx.DB) *dbCache {
+	cachesMu.Lock()
+	defer cachesMu.Unlock()
 
-		cache = c
+	if c, ok := caches[db]; ok {
+		return c
+	}
 
-	})
+	c := &dbCache{
+		records: make(map[string]any),
+		version: -1,
+		mu:      &sync.RWMutex{},
+	}
+
+	// PRAGMA data_version only changes for commits made by other connections,
+	// so a connection that never writes is kept to watch it
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		logrus.Errorf("Cache disabled, can't open connection: %v", err)
+	} else {
+		c.conn = conn
+	}
+
+	caches[db] = c
+
+	return c
+}
+
+// Drops the cached values if the db changed since they were read, if the
+// version can't be read nothing is kept
+func (c *dbCache) checkVersion() {
+	c.mu.RLock()
+	fresh := time.Since(c.checked) < versionTTL
+	c.mu.RUnlock()
+
+	if fresh {
+		return
+	}
 
-	return cache
+	version := c.readVersion()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version == -1 || version != c.version {
+		c.records = make(map[string]any)
+	}
+
+	c.version = version
+	c.checked = time.Now()
+}
+
+func (c *dbCache) readVersion() int64 {
+	if c.conn == nil {
+		return -1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	version := int64(-1)
+
+	err := c.conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version)
+	if err != nil {
+		logrus.Errorf("Can't read db data version: %v", err)
+		return -1
+	}
+
+	return version
+}
+
+// Removes the cache of the db and closes its connection, the db can't be
+// closed while the connection is kept
+func releaseDBCache(db *sqlx.DB) {
+	cachesMu.Lock()
+	c, ok := caches[db]
+	delete(caches, db)
+	cachesMu.Unlock()
+
+	if !ok || c.conn == nil {
+		return
+	}
+
+	err := c.conn.Close()
+	if err != nil {
+		logrus.Errorf("Can't close cache connection: %v", err)
+	}
 }
 
 func getFromCache[T any](c *dbCache, key string, value *T) bool {
+	c.checkVersion()
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -53,6 +142,10 @@ func setInCache[T any](c *dbCache, key string, value T) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.version == -1 {
+		return
+	}
+
 	c.records[key] = value
 }
 
@@ -89,6 +182,17 @@ func resetCache(c *dbCache) {
 	c.records = make(map[string]any)
 }
 
+// Drops the cached values of the db, if it has a cache
+func resetDBCache(db *sqlx.DB) {
+	cachesMu.Lock()
+	c, ok := caches[db]
+	cachesMu.Unlock()
+
+	if ok {
+		resetCache(c)
+	}
+}
+
 func withResetCache(c *dbCache, f func() error) error {
 	err := f()
 	if err != nil {
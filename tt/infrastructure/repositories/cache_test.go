@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+)
+
+func TestCacheSeesOtherConnections(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tt.db")
+
+	db, err := OpenSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("can't open db: %v", err)
+	}
+
+	stats := NewSQLiteStatsRepository(db, fixedClock(day(14, 12)))
+
+	hours, err := stats.GetHoursByDate(ctx, day(14, 0))
+	if err != nil || hours != 0 {
+		t.Fatalf("got %v hours (%v), want 0", hours, err)
+	}
+
+	// Another process writing on the same file
+	other, err := OpenSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("can't open db: %v", err)
+	}
+	defer CloseSQLiteDB(other)
+
+	record := domain.Must(domain.NewCloseRecord(day(14, 9), 2, domain.NewRecordInfo("", "", nil)))
+
+	err = NewSQLiteRecordRepository(other).Save(ctx, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(versionTTL)
+
+	hours, err = stats.GetHoursByDate(ctx, day(14, 0))
+	if err != nil || hours != 2 {
+		t.Errorf("got %v hours (%v), want 2", hours, err)
+	}
+
+	err = CloseSQLiteDB(db)
+	if err != nil {
+		t.Fatalf("can't close db: %v", err)
+	}
+
+	cachesMu.Lock()
+	_, ok := caches[db]
+	cachesMu.Unlock()
+
+	if ok {
+		t.Error("the cache of a closed db must be released")
+	}
+}
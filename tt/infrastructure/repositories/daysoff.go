@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SQLiteDayOffRepository struct {
+	db    *sqlx.DB
+	cache *dbCache
+}
+
+func NewSQLiteDayOffRepository(db *sqlx.DB) *SQLiteDayOffRepository {
+	return &SQLiteDayOffRepository{
+		db:    db,
+		cache: newDBCache(db),
+	}
+}
+
+func (r *SQLiteDayOffRepository) Save(ctx context.Context, days []*domain.DayOff) error {
+	return withResetCache(r.cache, func() error {
+		return withTx(ctx, r.db, func(ctx context.Context) error {
+			for _, day := range days {
+				_, err := dbOrTx(ctx, r.db).NamedExecContext(ctx,
+					`INSERT INTO days_off (date, reason) VALUES (:date, :reason)
+			ON CONFLICT(date) DO UPDATE SET reason = excluded.reason`,
+					newDBDayOff(day))
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// Deletes the days off between both dates, returns how many were deleted
+func (r *SQLiteDayOffRepository) Delete(ctx context.Context, from, to time.Time) (int, error) {
+	deleted := 0
+
+	err := withResetCache(r.cache, func() error {
+		result, err := dbOrTx(ctx, r.db).ExecContext(ctx, `DELETE FROM days_off WHERE date BETWEEN ? AND ?`,
+			from.Format(time.DateOnly), to.Format(time.DateOnly))
+		if err != nil {
+			return err
+		}
+
+		n, err := result.RowsAffected()
+		deleted = int(n)
+
+		return err
+	})
+
+	return deleted, err
+}
+
+func (r *SQLiteDayOffRepository) GetAll(ctx context.Context) ([]*domain.DayOff, error) {
+	key := "days-off:get-all"
+
+	return withCache(r.cache, key, func() ([]*domain.DayOff, error) {
+		var dbDays []*DBDayOff
+
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbDays, `SELECT date, reason FROM days_off ORDER BY date`)
+		if err != nil {
+			return nil, err
+		}
+
+		return toDomainDaysOff(dbDays)
+	})
+}
+
+func (r *SQLiteDayOffRepository) GetAllInRange(ctx context.Context, from, to time.Time) ([]*domain.DayOff, error) {
+	sfrom, sto := from.Format(time.DateOnly), to.Format(time.DateOnly)
+	key := fmt.Sprintf("days-off:get-all-range:%s:%s", sfrom, sto)
+
+	return withCache(r.cache, key, func() ([]*domain.DayOff, error) {
+		var dbDays []*DBDayOff
+
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbDays, `SELECT date, reason FROM days_off WHERE date BETWEEN ? AND ? ORDER BY date`, sfrom, sto)
+		if err != nil {
+			return nil, err
+		}
+
+		return toDomainDaysOff(dbDays)
+	})
+}
+
+func toDomainDaysOff(dbDays []*DBDayOff) ([]*domain.DayOff, error) {
+	days := make([]*domain.DayOff, len(dbDays))
+	for i, dbDay := range dbDays {
+		day, err := dbDay.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		days[i] = day
+	}
+
+	return days, nil
+}
@@ -10,36 +10,28 @@ import (
 )
 
 func NewSQLiteDB(name string) (*sqlx.DB, error) {
-	db, err := sqlx.Open("sqlite3", utils.GeAppPath(fmt.Sprintf("%s.db", name)))
+	return OpenSQLiteDB(utils.GeAppPath(fmt.Sprintf("%s.db", name)))
+}
+
+// Opens the db on the given path, migrating it to the current schema
+func OpenSQLiteDB(path string) (*sqlx.DB, error) {
+	db, err := sqlx.Open("sqlite3", path)
 	if err != nil {
 		return nil, err
 	}
 
-	err = createTables(db)
+	err = migrate(db, path)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	return db, nil
 }
 
-func createTables(db *sqlx.DB) error {
-	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS records (
-		id TEXT PRIMARY KEY,
-		date TEXT,
-		hours REAL
-	)`)
-	if err != nil {
-		return err
-	}
-
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS state_variables (
-		key TEXT PRIMARY KEY,
-		value TEXT
-	)`)
-	if err != nil {
-		return err
-	}
+// Closes the db and releases its cache
+func CloseSQLiteDB(db *sqlx.DB) error {
+	releaseDBCache(db)
 
-	return nil
+	return db.Close()
 }
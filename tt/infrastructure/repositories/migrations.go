@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sqlx.Tx) error
+}
+
+// Schema migrations, never change an existing one, append a new version instead.
+// The first versions are idempotent because databases created before the
+// schema was versioned already have part of them applied.
+var migrations = []migration{
+	{1, "initial schema", func(tx *sqlx.Tx) error {
+		return execAll(tx,
+			`CREATE TABLE IF NOT EXISTS records (
+				id TEXT PRIMARY KEY,
+				date TEXT,
+				hours REAL
+			)`,
+			`CREATE TABLE IF NOT EXISTS state_variables (
+				key TEXT PRIMARY KEY,
+				value TEXT
+			)`,
+		)
+	}},
+	{2, "records status", func(tx *sqlx.Tx) error {
+		err := addColumnIfNotExists(tx, "records", "status", `TEXT NOT NULL DEFAULT 'pending'`)
+		if err != nil {
+			return err
+		}
+
+		return execAll(tx, `CREATE INDEX IF NOT EXISTS records_status ON records (status)`)
+	}},
+	{3, "pool ledger", func(tx *sqlx.Tx) error {
+		return execAll(tx,
+			`CREATE TABLE IF NOT EXISTS pool (
+				id TEXT PRIMARY KEY,
+				date TEXT,
+				hours REAL,
+				movement TEXT
+			)`,
+		)
+	}},
+	{4, "templates", func(tx *sqlx.Tx) error {
+		return execAll(tx,
+			`CREATE TABLE IF NOT EXISTS templates (
+				name TEXT PRIMARY KEY,
+				description TEXT,
+				project TEXT,
+				hours REAL
+			)`,
+		)
+	}},
+	{5, "records info", func(tx *sqlx.Tx) error {
+		for _, column := range []string{"description", "project", "tags"} {
+			err := addColumnIfNotExists(tx, "records", column, `TEXT NOT NULL DEFAULT ''`)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}},
+	{6, "records end date", func(tx *sqlx.Tx) error {
+		err := addColumnIfNotExists(tx, "records", "end_date", `TEXT NOT NULL DEFAULT ''`)
+		if err != nil {
+			return err
+		}
+
+		return fillRecordsEndDate(tx)
+	}},
+	{7, "days off", func(tx *sqlx.Tx) error {
+		return execAll(tx,
+			`CREATE TABLE IF NOT EXISTS days_off (
+				date TEXT PRIMARY KEY,
+				reason TEXT NOT NULL DEFAULT ''
+			)`,
+		)
+	}},
+}
+
+func latestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// Brings the schema to the latest version, backing up the db file first
+func migrate(db *sqlx.DB, path string) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT,
+		applied_at TEXT
+	)`)
+	if err != nil {
+		return err
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	latest := latestVersion()
+
+	if current > latest {
+		return fmt.Errorf("database schema version %d is newer than the supported version %d, update tt to use this database", current, latest)
+	}
+
+	if current == latest {
+		return nil
+	}
+
+	empty, err := isEmptyDB(db)
+	if err != nil {
+		return err
+	}
+
+	if !empty {
+		backup := fmt.Sprintf("%s.v%d-%s.bak", path, current, time.Now().Format("20060102150405"))
+
+		_, err = db.Exec(`VACUUM INTO ?`, backup)
+		if err != nil {
+			return fmt.Errorf("can't backup database before upgrading, %w", err)
+		}
+
+		logrus.Infof("Database backed up to %s before upgrading from version %d to %d", backup, current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		err = applyMigration(db, m)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed, %w", m.version, m.name, err)
+		}
+
+		logrus.Infof("Database migrated to version %d (%s)", m.version, m.name)
+	}
+
+	return nil
+}
+
+func applyMigration(db *sqlx.DB, m migration) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = m.up(tx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, time.Now().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func schemaVersion(db *sqlx.DB) (int, error) {
+	var version *int
+
+	err := db.Get(&version, `SELECT MAX(version) FROM schema_version`)
+	if err != nil {
+		return 0, err
+	}
+
+	if version == nil {
+		return 0, nil
+	}
+
+	return *version, nil
+}
+
+// A db without data tables is new and has nothing to back up
+func isEmptyDB(db *sqlx.DB) (bool, error) {
+	var count int
+
+	err := db.Get(&count, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name != 'schema_version'`)
+	if err != nil {
+		return false, err
+	}
+
+	return count == 0, nil
+}
+
+func execAll(tx *sqlx.Tx, queries ...string) error {
+	for _, query := range queries {
+		_, err := tx.Exec(query)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Adds a column to a table created by an older version of the app
+func addColumnIfNotExists(tx *sqlx.Tx, table, column, definition string) error {
+	var count int
+	err := tx.Get(&count, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+
+	return err
+}
+
+// Records created before the end date was stored end at their start plus their hours
+func fillRecordsEndDate(tx *sqlx.Tx) error {
+	var dbRecords []*DBRecord
+
+	err := tx.Select(&dbRecords, `SELECT id, date, hours FROM records WHERE end_date = ''`)
+	if err != nil {
+		return err
+	}
+
+	for _, dbRecord := range dbRecords {
+		date, err := time.Parse(time.RFC3339, dbRecord.Date)
+		if err != nil {
+			return err
+		}
+
+		end := date.Add(time.Duration(dbRecord.Hours * float64(time.Hour)))
+
+		_, err = tx.Exec(`UPDATE records SET end_date = ? WHERE id = ?`, end.Format(time.RFC3339), dbRecord.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
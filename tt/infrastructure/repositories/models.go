@@ -1,16 +1,138 @@
 package repositories
 
+import (
+	"strings"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+)
+
+const recordColumns = "id, date, end_date, hours, status, description, project, tags"
+
 type DBRecord struct {
-	Id    string  `db:"id"`
-	Date  string  `db:"date"`
-	Hours float64 `db:"hours"`
+	Id          string  `db:"id"`
+	Date        string  `db:"date"`
+	EndDate     string  `db:"end_date"`
+	Hours       float64 `db:"hours"`
+	Status      string  `db:"status"`
+	Description string  `db:"description"`
+	Project     string  `db:"project"`
+	Tags        string  `db:"tags"`
+}
+
+func newDBRecord(record *domain.Record) DBRecord {
+	return DBRecord{
+		Id:          record.ID(),
+		Date:        record.Date().Format(time.RFC3339),
+		EndDate:     record.End().Format(time.RFC3339),
+		Hours:       record.Hours(),
+		Status:      record.Status().String(),
+		Description: record.Info().Description(),
+		Project:     record.Info().Project(),
+		Tags:        strings.Join(record.Info().Tags(), ","),
+	}
+}
+
+func (r *DBRecord) toDomain() (*domain.Record, error) {
+	date, err := time.Parse(time.RFC3339, r.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := time.Parse(time.RFC3339, r.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	info := domain.NewRecordInfo(r.Description, r.Project, domain.ParseTags(r.Tags))
+
+	return domain.RecreateRecord(r.Id, date, end, r.Status, info)
+}
+
+func toDomainRecords(dbRecords []*DBRecord) ([]*domain.Record, error) {
+	records := make([]*domain.Record, len(dbRecords))
+	for i, dbRecord := range dbRecords {
+		record, err := dbRecord.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+
+	return records, nil
 }
 
 type DBOpenRecord struct {
-	Date string `db:"date"`
+	Date        string `db:"date"`
+	Description string `db:"description"`
+	Project     string `db:"project"`
+	Tags        string `db:"tags"`
+}
+
+type DBHoursSummary struct {
+	Date    string  `db:"date"`
+	Project string  `db:"project"`
+	Status  string  `db:"status"`
+	Hours   float64 `db:"hours"`
+}
+
+func (s *DBHoursSummary) toDomain() (domain.HoursSummary, error) {
+	date, err := time.ParseInLocation(time.DateOnly, s.Date, time.Local)
+	if err != nil {
+		return domain.HoursSummary{}, err
+	}
+
+	status, err := domain.NewRecordStatus(s.Status)
+	if err != nil {
+		return domain.HoursSummary{}, err
+	}
+
+	return domain.HoursSummary{Date: date, Project: s.Project, Status: status, Hours: s.Hours}, nil
+}
+
+type DBPoolEntry struct {
+	Id       string  `db:"id"`
+	Date     string  `db:"date"`
+	Hours    float64 `db:"hours"`
+	Movement string  `db:"movement"`
+}
+
+func (e *DBPoolEntry) toDomain() (*domain.PoolEntry, error) {
+	date, err := time.Parse(time.RFC3339, e.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.RecreatePoolEntry(e.Id, date, e.Hours, e.Movement)
+}
+
+type DBTemplate struct {
+	Name        string  `db:"name"`
+	Description string  `db:"description"`
+	Project     string  `db:"project"`
+	Hours       float64 `db:"hours"`
+}
+
+func (t *DBTemplate) toDomain() (*domain.Template, error) {
+	return domain.NewTemplate(t.Name, t.Description, t.Project, t.Hours)
+}
+
+type DBDayOff struct {
+	Date   string `db:"date"`
+	Reason string `db:"reason"`
+}
+
+func newDBDayOff(day *domain.DayOff) DBDayOff {
+	return DBDayOff{
+		Date:   day.Date().Format(time.DateOnly),
+		Reason: day.Reason(),
+	}
 }
 
-type DBDebt struct {
-	StartDate string  `db:"date"`
-	Hours     float64 `db:"hours"`
+func (d *DBDayOff) toDomain() (*domain.DayOff, error) {
+	date, err := time.ParseInLocation(time.DateOnly, d.Date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewDayOff(date, d.Reason), nil
 }
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SQLitePoolRepository struct {
+	db    *sqlx.DB
+	cache *dbCache
+}
+
+func NewSQLitePoolRepository(db *sqlx.DB) *SQLitePoolRepository {
+	return &SQLitePoolRepository{
+		db:    db,
+		cache: newDBCache(db),
+	}
+}
+
+func (r *SQLitePoolRepository) Save(ctx context.Context, entry *domain.PoolEntry) error {
+	return withResetCache(r.cache, func() error {
+		dbEntry := DBPoolEntry{
+			Id:       entry.ID(),
+			Date:     entry.Date().Format(time.RFC3339),
+			Hours:    entry.Hours(),
+			Movement: entry.Movement().String(),
+		}
+
+		_, err := dbOrTx(ctx, r.db).NamedExecContext(ctx,
+			`INSERT INTO pool (id, date, hours, movement) VALUES (:id, :date, :hours, :movement)
+		ON CONFLICT(id) DO UPDATE SET date = excluded.date, hours = excluded.hours, movement = excluded.movement`,
+			dbEntry)
+
+		return err
+	})
+}
+
+func (r *SQLitePoolRepository) GetAll(ctx context.Context) ([]*domain.PoolEntry, error) {
+	key := "pool:get-all"
+
+	return withCache(r.cache, key, func() ([]*domain.PoolEntry, error) {
+		var dbEntries []*DBPoolEntry
+
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbEntries, `SELECT id, date, hours, movement FROM pool ORDER BY date`)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]*domain.PoolEntry, len(dbEntries))
+		for i, dbEntry := range dbEntries {
+			entry, err := dbEntry.toDomain()
+			if err != nil {
+				return nil, err
+			}
+			entries[i] = entry
+		}
+
+		return entries, nil
+	})
+}
+
+func (r *SQLitePoolRepository) GetBalance(ctx context.Context) (float64, error) {
+	key := "pool:balance"
+
+	return withCache(r.cache, key, func() (float64, error) {
+		var balance *float64
+
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &balance, `SELECT SUM(CASE WHEN movement = ? THEN -hours ELSE hours END) FROM pool`, domain.PoolOut.String())
+		if err != nil {
+			return 0, err
+		}
+
+		if balance == nil {
+			return 0, nil
+		}
+
+		return *balance, nil
+	})
+}
@@ -19,21 +19,19 @@ type SQLiteRecordRepository struct {
 func NewSQLiteRecordRepository(db *sqlx.DB) *SQLiteRecordRepository {
 	return &SQLiteRecordRepository{
 		db:    db,
-		cache: newDBCache(),
+		cache: newDBCache(db),
 	}
 }
 
 func (r *SQLiteRecordRepository) Save(ctx context.Context, record *domain.Record) error {
 	return withResetCache(r.cache, func() error {
-		dbRecord := DBRecord{
-			Id:    record.ID(),
-			Date:  record.Date().Format(time.RFC3339),
-			Hours: record.Hours(),
-		}
+		dbRecord := newDBRecord(record)
 
-		_, err := r.db.NamedExecContext(ctx,
-			`INSERT INTO records (id, date, hours) VALUES (:id, :date, :hours)
-		ON CONFLICT(id) DO UPDATE SET date = excluded.date, hours = excluded.hours`,
+		_, err := dbOrTx(ctx, r.db).NamedExecContext(ctx,
+			`INSERT INTO records (id, date, end_date, hours, status, description, project, tags)
+		VALUES (:id, :date, :end_date, :hours, :status, :description, :project, :tags)
+		ON CONFLICT(id) DO UPDATE SET date = excluded.date, end_date = excluded.end_date, hours = excluded.hours, status = excluded.status,
+		description = excluded.description, project = excluded.project, tags = excluded.tags`,
 			dbRecord)
 
 		return err
@@ -42,7 +40,7 @@ func (r *SQLiteRecordRepository) Save(ctx context.Context, record *domain.Record
 
 func (r *SQLiteRecordRepository) Delete(ctx context.Context, id string) error {
 	return withResetCache(r.cache, func() error {
-		_, err := r.db.ExecContext(ctx, `DELETE FROM records WHERE id = ?`, id)
+		_, err := dbOrTx(ctx, r.db).ExecContext(ctx, `DELETE FROM records WHERE id = ?`, id)
 		return err
 	})
 }
@@ -53,17 +51,16 @@ func (r *SQLiteRecordRepository) Get(ctx context.Context, id string) (*domain.Re
 	return withCache(r.cache, key, func() (*domain.Record, error) {
 		var dbRecord DBRecord
 
-		err := r.db.GetContext(ctx, &dbRecord, `SELECT id, date, hours FROM records WHERE id = ?`, id)
-		if err != nil {
-			return nil, err
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &dbRecord, `SELECT `+recordColumns+` FROM records WHERE id = ?`, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
 		}
 
-		date, err := time.Parse(time.RFC3339, dbRecord.Date)
 		if err != nil {
 			return nil, err
 		}
 
-		return domain.RecreateRecord(dbRecord.Id, date, dbRecord.Hours)
+		return dbRecord.toDomain()
 	})
 }
 
@@ -73,57 +70,57 @@ func (r *SQLiteRecordRepository) GetAllByDate(ctx context.Context, date time.Tim
 	return withCache(r.cache, key, func() ([]*domain.Record, error) {
 		var dbRecords []*DBRecord
 
-		err := r.db.SelectContext(ctx, &dbRecords, `SELECT id, date, hours FROM records WHERE SUBSTR(date,1,10) = SUBSTR(?,1,10)`, date.Format(time.RFC3339))
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbRecords, `SELECT `+recordColumns+` FROM records WHERE SUBSTR(date,1,10) = SUBSTR(?,1,10) ORDER BY date`, date.Format(time.RFC3339))
 		if err != nil {
 			return nil, err
 		}
 
-		records := make([]*domain.Record, len(dbRecords))
-		for i, dbRecord := range dbRecords {
-			date, err := time.Parse(time.RFC3339, dbRecord.Date)
-			if err != nil {
-				return nil, err
-			}
-
-			record, err := domain.RecreateRecord(dbRecord.Id, date, dbRecord.Hours)
-			if err != nil {
-				return nil, err
-			}
-			records[i] = record
-		}
-
-		return records, nil
+		return toDomainRecords(dbRecords)
 	})
 }
 
-func (r *SQLiteRecordRepository) GetHours(ctx context.Context) (float64, error) {
-	key := "get:hours"
-	openRecord, err := withCache(r.cache, key, func() (*domain.OpenRecord, error) {
-		var dbOpenRecord DBOpenRecord
-		err := r.db.GetContext(ctx, &dbOpenRecord, `SELECT value as date FROM state_variables WHERE key = 'open_record_start_time'`)
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
+func (r *SQLiteRecordRepository) GetAllByDateAndStatus(ctx context.Context, date time.Time, status domain.RecordStatus) ([]*domain.Record, error) {
+	key := fmt.Sprintf("get-all:%s:%s", date.Format("060102"), status)
+
+	return withCache(r.cache, key, func() ([]*domain.Record, error) {
+		var dbRecords []*DBRecord
 
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbRecords, `SELECT `+recordColumns+` FROM records WHERE SUBSTR(date,1,10) = SUBSTR(?,1,10) AND status = ? ORDER BY date`, date.Format(time.RFC3339), status.String())
 		if err != nil {
 			return nil, err
 		}
 
-		date, err := time.Parse(time.RFC3339, dbOpenRecord.Date)
+		return toDomainRecords(dbRecords)
+	})
+}
+
+func (r *SQLiteRecordRepository) GetAllByStatus(ctx context.Context, status domain.RecordStatus) ([]*domain.Record, error) {
+	key := fmt.Sprintf("get-all-status:%s", status)
+
+	return withCache(r.cache, key, func() ([]*domain.Record, error) {
+		var dbRecords []*DBRecord
+
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbRecords, `SELECT `+recordColumns+` FROM records WHERE status = ? ORDER BY date`, status.String())
 		if err != nil {
 			return nil, err
 		}
 
-		return domain.NewOpenRecord(date), nil
+		return toDomainRecords(dbRecords)
 	})
+}
 
-	if err != nil {
-		return 0, err
-	}
+func (r *SQLiteRecordRepository) GetAllInRange(ctx context.Context, from, to time.Time) ([]*domain.Record, error) {
+	sfrom, sto := from.Format(time.DateOnly), to.Format(time.DateOnly)
+	key := fmt.Sprintf("get-all-range:%s:%s", sfrom, sto)
 
-	if openRecord == nil {
-		return 0, nil
-	}
+	return withCache(r.cache, key, func() ([]*domain.Record, error) {
+		var dbRecords []*DBRecord
 
-	return openRecord.Hours(), nil
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbRecords, `SELECT `+recordColumns+` FROM records WHERE SUBSTR(date,1,10) BETWEEN ? AND ? ORDER BY date`, sfrom, sto)
+		if err != nil {
+			return nil, err
+		}
+
+		return toDomainRecords(dbRecords)
+	})
 }
@@ -14,12 +14,14 @@ import (
 type SQLiteStatsRepository struct {
 	db    *sqlx.DB
 	cache *dbCache
+	clock domain.Clock
 }
 
-func NewSQLiteStatsRepository(db *sqlx.DB) *SQLiteStatsRepository {
+func NewSQLiteStatsRepository(db *sqlx.DB, clock domain.Clock) *SQLiteStatsRepository {
 	return &SQLiteStatsRepository{
 		db:    db,
-		cache: newDBCache(),
+		cache: newDBCache(db),
+		clock: clock,
 	}
 }
 
@@ -29,7 +31,7 @@ func (r *SQLiteStatsRepository) GetHoursByDate(ctx context.Context, date time.Ti
 	return withCache(r.cache, key, func() (float64, error) {
 		var totalHours *float64
 
-		err := r.db.GetContext(ctx, &totalHours, `SELECT SUM(hours) FROM records WHERE SUBSTR(date,1,10) = SUBSTR(?,1,10)`, date.Format(time.RFC3339))
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &totalHours, `SELECT SUM(hours) FROM records WHERE SUBSTR(date,1,10) = SUBSTR(?,1,10)`, date.Format(time.RFC3339))
 		if err != nil {
 			return 0, err
 		}
@@ -42,61 +44,141 @@ func (r *SQLiteStatsRepository) GetHoursByDate(ctx context.Context, date time.Ti
 	})
 }
 
-func (r *SQLiteStatsRepository) GetDebt(ctx context.Context, workingTime float64) (float64, error) {
-	key := "get-debts"
+func (r *SQLiteStatsRepository) GetHoursByDateAndStatus(ctx context.Context, date time.Time, status domain.RecordStatus) (float64, error) {
+	key := fmt.Sprintf("get-hours:%s:%s", date.Format("060102"), status)
 
-	dbDebt, err := withCache(r.cache, key, func() (*DBDebt, error) {
-		var dbDebt DBDebt
-		err := r.db.GetContext(ctx, &dbDebt, `
-			SELECT min(date) date,sum(hours) hours FROM records
-		`, workingTime)
+	return withCache(r.cache, key, func() (float64, error) {
+		var totalHours *float64
+
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &totalHours, `SELECT SUM(hours) FROM records WHERE SUBSTR(date,1,10) = SUBSTR(?,1,10) AND status = ?`, date.Format(time.RFC3339), status.String())
+		if err != nil {
+			return 0, err
+		}
+
+		if totalHours == nil {
+			return 0, nil
+		}
+
+		return *totalHours, nil
+	})
+}
+
+func (r *SQLiteStatsRepository) GetHoursByStatus(ctx context.Context, status domain.RecordStatus) (float64, error) {
+	key := fmt.Sprintf("get-hours-status:%s", status)
+
+	return withCache(r.cache, key, func() (float64, error) {
+		var totalHours *float64
+
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &totalHours, `SELECT SUM(hours) FROM records WHERE status = ?`, status.String())
+		if err != nil {
+			return 0, err
+		}
+
+		if totalHours == nil {
+			return 0, nil
+		}
+
+		return *totalHours, nil
+	})
+}
+
+func (r *SQLiteStatsRepository) GetHoursSummary(ctx context.Context, from, to time.Time) ([]domain.HoursSummary, error) {
+	sfrom, sto := from.Format(time.DateOnly), to.Format(time.DateOnly)
+	key := fmt.Sprintf("get-hours-summary:%s:%s", sfrom, sto)
+
+	return withCache(r.cache, key, func() ([]domain.HoursSummary, error) {
+		var dbSummaries []*DBHoursSummary
+
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbSummaries, `
+			SELECT SUBSTR(date,1,10) date, project, status, SUM(hours) hours FROM records
+			WHERE SUBSTR(date,1,10) BETWEEN ? AND ?
+			GROUP BY SUBSTR(date,1,10), project, status
+			ORDER BY date
+		`, sfrom, sto)
 		if err != nil {
 			return nil, err
 		}
 
-		return &dbDebt, nil
+		summaries := make([]domain.HoursSummary, len(dbSummaries))
+		for i, dbSummary := range dbSummaries {
+			summary, err := dbSummary.toDomain()
+			if err != nil {
+				return nil, err
+			}
+			summaries[i] = summary
+		}
+
+		return summaries, nil
 	})
+}
 
-	if err != nil {
-		return 0, err
+// Balance of the days between from and to. A zero from starts at the first
+// record and a zero to ends today, the open record counts as recorded today.
+func (r *SQLiteStatsRepository) GetDebt(ctx context.Context, from, to time.Time, schedule domain.Schedule, holidays []time.Time) (*domain.Debt, error) {
+	now := r.clock.Now()
+
+	if to.IsZero() {
+		to = now
+	}
+
+	if from.IsZero() {
+		first, err := r.getFirstRecordDate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if first == nil {
+			return &domain.Debt{From: domain.StartOfDay(to), To: domain.StartOfDay(to)}, nil
+		}
+
+		from = *first
 	}
 
-	startDate, err := time.Parse(time.RFC3339, dbDebt.StartDate)
+	summaries, err := r.GetHoursSummary(ctx, from, to)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	expectedHours := getExpectedHours(workingTime, startDate)
-	debt := expectedHours - dbDebt.Hours
+	debt := domain.NewDebt(from, to, now, summaries, schedule, holidays)
 
 	trackedHours, err := r.GetTrackedHours(ctx)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return debt - trackedHours, nil
+	debt.AddRecorded(now, trackedHours)
+
+	return debt, nil
 }
 
-func getExpectedHours(workingTime float64, startDate time.Time) float64 {
-	now := time.Now()
+// Date of the first record that is not on the pool, nil when there are none
+func (r *SQLiteStatsRepository) getFirstRecordDate(ctx context.Context) (*time.Time, error) {
+	return withCache(r.cache, "get-first-date", func() (*time.Time, error) {
+		var sdate *string
 
-	total := 0
-	for date := startDate; date.Before(now); date = date.AddDate(0, 0, 1) {
-		if domain.IsWeekend(date) {
-			continue
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &sdate, `SELECT MIN(SUBSTR(date,1,10)) FROM records WHERE status != ?`, domain.Pool.String())
+		if err != nil {
+			return nil, err
 		}
 
-		total++
-	}
+		if sdate == nil {
+			return nil, nil
+		}
 
-	return float64(total) * workingTime
+		date, err := time.ParseInLocation(time.DateOnly, *sdate, time.Local)
+		if err != nil {
+			return nil, err
+		}
+
+		return &date, nil
+	})
 }
 
 func (r *SQLiteStatsRepository) GetTrackedHours(ctx context.Context) (float64, error) {
 	key := "get:hours"
 	openRecord, err := withCache(r.cache, key, func() (*domain.OpenRecord, error) {
 		var dbOpenRecord DBOpenRecord
-		err := r.db.GetContext(ctx, &dbOpenRecord, `SELECT value as date FROM state_variables WHERE key = 'open_record_start_time'`)
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &dbOpenRecord, `SELECT value as date FROM state_variables WHERE key = 'open_record_start_time'`)
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -121,5 +203,5 @@ func (r *SQLiteStatsRepository) GetTrackedHours(ctx context.Context) (float64, e
 		return 0, nil
 	}
 
-	return openRecord.Hours(), nil
+	return openRecord.Hours(r.clock.Now()), nil
 }
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"varmijo/time-tracker/tt/domain"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func day(d, hour int) time.Time {
+	return time.Date(2026, time.October, d, hour, 0, 0, 0, time.Local)
+}
+
+type testRecord struct {
+	date   time.Time
+	hours  float64
+	status domain.RecordStatus
+}
+
+func TestGetDebt(t *testing.T) {
+	// Wednesday at noon
+	now := day(14, 12)
+
+	week := []testRecord{
+		{date: day(12, 9), hours: 8, status: domain.Committed},
+		{date: day(13, 9), hours: 5, status: domain.Pending},
+		{date: day(13, 15), hours: 1, status: domain.Pending},
+		{date: day(11, 9), hours: 3, status: domain.Pool},
+	}
+
+	tests := []struct {
+		name     string
+		records  []testRecord
+		tracking time.Time
+		from, to time.Time
+		expected float64
+		recorded float64
+		days     int
+	}{
+		{
+			name: "empty db",
+		},
+		{
+			name:    "only pool records",
+			records: []testRecord{{date: day(11, 9), hours: 3, status: domain.Pool}},
+		},
+		{
+			name:     "since the first record",
+			records:  week,
+			expected: 16,
+			recorded: 14,
+			days:     3,
+		},
+		{
+			name:     "tracked hours count today",
+			records:  week,
+			tracking: day(14, 10),
+			expected: 18,
+			recorded: 16,
+			days:     3,
+		},
+		{
+			name:     "range",
+			records:  week,
+			from:     day(13, 0),
+			to:       day(13, 0),
+			expected: 8,
+			recorded: 6,
+			days:     1,
+		},
+		{
+			name:     "range from a date to today",
+			records:  week,
+			from:     day(9, 0),
+			expected: 24,
+			recorded: 14,
+			days:     6,
+		},
+		{
+			name:     "range without records",
+			from:     day(5, 0),
+			to:       day(9, 0),
+			expected: 40,
+			days:     5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			db, err := OpenSQLiteDB(filepath.Join(t.TempDir(), "tt.db"))
+			if err != nil {
+				t.Fatalf("can't open db: %v", err)
+			}
+			t.Cleanup(func() { CloseSQLiteDB(db) })
+
+			records := NewSQLiteRecordRepository(db)
+			for _, r := range tt.records {
+				record, err := domain.NewCloseRecord(r.date, r.hours, domain.NewRecordInfo("", "", nil))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				switch r.status {
+				case domain.Committed:
+					err = record.Commit()
+				case domain.Pool:
+					err = record.SendToPool()
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = records.Save(ctx, record)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !tt.tracking.IsZero() {
+				err = NewSQLiteTrackRepository(db).Save(ctx, domain.NewOpenRecord(tt.tracking))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stats := NewSQLiteStatsRepository(db, fixedClock(now))
+
+			debt, err := stats.GetDebt(ctx, tt.from, tt.to, domain.NewFlatSchedule(8), nil)
+			if err != nil {
+				t.Fatalf("can't get debt: %v", err)
+			}
+
+			if len(debt.Days) != tt.days {
+				t.Fatalf("got %d days, want %d", len(debt.Days), tt.days)
+			}
+
+			total := debt.Total()
+			if total.Expected != tt.expected {
+				t.Errorf("expected %v hours, want %v", total.Expected, tt.expected)
+			}
+
+			if total.Recorded != tt.recorded {
+				t.Errorf("recorded %v hours, want %v", total.Recorded, tt.recorded)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"varmijo/time-tracker/tt/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SQLiteTemplateRepository struct {
+	db    *sqlx.DB
+	cache *dbCache
+}
+
+func NewSQLiteTemplateRepository(db *sqlx.DB) *SQLiteTemplateRepository {
+	return &SQLiteTemplateRepository{
+		db:    db,
+		cache: newDBCache(db),
+	}
+}
+
+func (r *SQLiteTemplateRepository) Save(ctx context.Context, template *domain.Template) error {
+	return withResetCache(r.cache, func() error {
+		dbTemplate := DBTemplate{
+			Name:        template.Name(),
+			Description: template.Description(),
+			Project:     template.Project(),
+			Hours:       template.Hours(),
+		}
+
+		_, err := dbOrTx(ctx, r.db).NamedExecContext(ctx,
+			`INSERT INTO templates (name, description, project, hours) VALUES (:name, :description, :project, :hours)
+		ON CONFLICT(name) DO UPDATE SET description = excluded.description, project = excluded.project, hours = excluded.hours`,
+			dbTemplate)
+
+		return err
+	})
+}
+
+func (r *SQLiteTemplateRepository) Get(ctx context.Context, name string) (*domain.Template, error) {
+	key := fmt.Sprintf("template:get:%s", name)
+
+	return withCache(r.cache, key, func() (*domain.Template, error) {
+		var dbTemplate DBTemplate
+
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &dbTemplate, `SELECT name, description, project, hours FROM templates WHERE name = ?`, name)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("template %q not found", name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return dbTemplate.toDomain()
+	})
+}
+
+func (r *SQLiteTemplateRepository) GetAll(ctx context.Context) ([]*domain.Template, error) {
+	key := "template:get-all"
+
+	return withCache(r.cache, key, func() ([]*domain.Template, error) {
+		var dbTemplates []*DBTemplate
+
+		err := dbOrTx(ctx, r.db).SelectContext(ctx, &dbTemplates, `SELECT name, description, project, hours FROM templates ORDER BY name`)
+		if err != nil {
+			return nil, err
+		}
+
+		templates := make([]*domain.Template, len(dbTemplates))
+		for i, dbTemplate := range dbTemplates {
+			template, err := dbTemplate.toDomain()
+			if err != nil {
+				return nil, err
+			}
+			templates[i] = template
+		}
+
+		return templates, nil
+	})
+}
@@ -2,6 +2,9 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"strings"
 	"time"
 	"varmijo/time-tracker/tt/domain"
 
@@ -17,21 +20,43 @@ type SQLiteTrackRepository struct {
 func NewSQLiteTrackRepository(db *sqlx.DB) *SQLiteTrackRepository {
 	return &SQLiteTrackRepository{
 		db:    db,
-		cache: newDBCache(),
+		cache: newDBCache(db),
 	}
 }
 
+// State variables holding the optional info of the open record
+var openRecordInfoKeys = []string{"open_record_description", "open_record_project", "open_record_tags"}
+
 func (r *SQLiteTrackRepository) Save(ctx context.Context, openRecord *domain.OpenRecord) error {
 	return withResetCache(r.cache, func() error {
-		dbOpenRecord := DBOpenRecord{
-			Date: openRecord.Date().Format(time.RFC3339),
+		info := openRecord.Info()
+
+		values := map[string]string{
+			"open_record_start_time":  openRecord.Date().Format(time.RFC3339),
+			"open_record_description": info.Description(),
+			"open_record_project":     info.Project(),
+			"open_record_tags":        strings.Join(info.Tags(), ","),
 		}
 
-		_, err := r.db.NamedExecContext(ctx,
-			`INSERT INTO state_variables (key, value) VALUES ('open_record_start_time', :date)`,
-			dbOpenRecord)
+		return withTx(ctx, r.db, func(ctx context.Context) error {
+			var err error
 
-		return err
+			for key, value := range values {
+				if value == "" {
+					_, err = dbOrTx(ctx, r.db).ExecContext(ctx, `DELETE FROM state_variables WHERE key = ?`, key)
+				} else {
+					_, err = dbOrTx(ctx, r.db).ExecContext(ctx,
+						`INSERT INTO state_variables (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+						key, value)
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
 	})
 }
 
@@ -39,23 +64,38 @@ func (r *SQLiteTrackRepository) Get(ctx context.Context) (*domain.OpenRecord, er
 	key := "get:open_record"
 	return withCache(r.cache, key, func() (*domain.OpenRecord, error) {
 		var dbOpenRecord DBOpenRecord
-		err := r.db.GetContext(ctx, &dbOpenRecord, `SELECT value as date FROM state_variables WHERE key = 'open_record_start_time'`)
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &dbOpenRecord, `SELECT value as date FROM state_variables WHERE key = 'open_record_start_time'`)
 		if err != nil {
 			return nil, err
 		}
 
+		infoValues := []*string{&dbOpenRecord.Description, &dbOpenRecord.Project, &dbOpenRecord.Tags}
+		for i, infoKey := range openRecordInfoKeys {
+			err = dbOrTx(ctx, r.db).GetContext(ctx, infoValues[i], `SELECT value FROM state_variables WHERE key = ?`, infoKey)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+		}
+
 		date, err := time.Parse(time.RFC3339, dbOpenRecord.Date)
 		if err != nil {
 			return nil, err
 		}
 
-		return domain.NewOpenRecord(date), nil
+		info := domain.NewRecordInfo(dbOpenRecord.Description, dbOpenRecord.Project, domain.ParseTags(dbOpenRecord.Tags))
+
+		return domain.RecreateOpenRecord(date, info), nil
 	})
 }
 
 func (r *SQLiteTrackRepository) Delete(ctx context.Context) error {
 	return withResetCache(r.cache, func() error {
-		_, err := r.db.ExecContext(ctx, `DELETE FROM state_variables WHERE key = 'open_record_start_time'`)
+		query, args, err := sqlx.In(`DELETE FROM state_variables WHERE key IN (?)`, append([]string{"open_record_start_time"}, openRecordInfoKeys...))
+		if err != nil {
+			return err
+		}
+
+		_, err = dbOrTx(ctx, r.db).ExecContext(ctx, query, args...)
 		return err
 	})
 }
@@ -64,7 +104,7 @@ func (r *SQLiteTrackRepository) IsWorking(ctx context.Context) bool {
 	key := "get:is_working"
 	return withCacheMust(r.cache, key, func() bool {
 		var count int
-		err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM state_variables WHERE key = 'open_record_start_time'`)
+		err := dbOrTx(ctx, r.db).GetContext(ctx, &count, `SELECT COUNT(*) FROM state_variables WHERE key = 'open_record_start_time'`)
 		if err != nil {
 			return false
 		}
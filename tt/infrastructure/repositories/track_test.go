@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"varmijo/time-tracker/tt/domain"
+)
+
+func TestTrackDelete(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := OpenSQLiteDB(filepath.Join(t.TempDir(), "tt.db"))
+	if err != nil {
+		t.Fatalf("can't open db: %v", err)
+	}
+	t.Cleanup(func() { CloseSQLiteDB(db) })
+
+	// Matches open_record_% if _ is taken as a wildcard
+	_, err = db.ExecContext(ctx, `INSERT INTO state_variables (key, value) VALUES ('openxrecordxnote', 'kept')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	track := NewSQLiteTrackRepository(db)
+
+	err = track.Save(ctx, domain.RecreateOpenRecord(day(14, 9), domain.NewRecordInfo("Review", "acme", []string{"docs"})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = track.Delete(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if track.IsWorking(ctx) {
+		t.Error("the open record must be deleted")
+	}
+
+	var keys []string
+
+	err = db.SelectContext(ctx, &keys, `SELECT key FROM state_variables WHERE key LIKE 'open%'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 || keys[0] != "openxrecordxnote" {
+		t.Errorf("got state variables %v, want only openxrecordxnote", keys)
+	}
+}
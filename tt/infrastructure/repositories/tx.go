@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Queries of the repositories, run on the db or on its transaction
+type querier interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+}
+
+type txKey struct {
+	db *sqlx.DB
+}
+
+// Transaction of the db started on the context, or the db itself
+func dbOrTx(ctx context.Context, db *sqlx.DB) querier {
+	if tx, ok := ctx.Value(txKey{db}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// Runs f in a transaction of the db, the repositories called with the
+// context given to f join it. If the context is already in a transaction f
+// is part of it. The cache is dropped when it ends, as it may hold values
+// read inside a rolled back transaction.
+func withTx(ctx context.Context, db *sqlx.DB, f func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{db}).(*sqlx.Tx); ok {
+		return f(ctx)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't start transaction, %w", err)
+	}
+	defer resetDBCache(db)
+	defer tx.Rollback()
+
+	err = f(context.WithValue(ctx, txKey{db}, tx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("can't commit transaction, %w", err)
+	}
+
+	return nil
+}
+
+type SQLiteTransactor struct {
+	db *sqlx.DB
+}
+
+func NewSQLiteTransactor(db *sqlx.DB) *SQLiteTransactor {
+	return &SQLiteTransactor{db: db}
+}
+
+func (t *SQLiteTransactor) WithTx(ctx context.Context, f func(ctx context.Context) error) error {
+	return withTx(ctx, t.db, f)
+}
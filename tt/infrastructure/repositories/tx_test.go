@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"varmijo/time-tracker/tt/domain"
+)
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := OpenSQLiteDB(filepath.Join(t.TempDir(), "tt.db"))
+	if err != nil {
+		t.Fatalf("can't open db: %v", err)
+	}
+	t.Cleanup(func() { CloseSQLiteDB(db) })
+
+	records := NewSQLiteRecordRepository(db)
+	pool := NewSQLitePoolRepository(db)
+	tx := NewSQLiteTransactor(db)
+
+	record := domain.Must(domain.NewCloseRecord(day(14, 9), 2, domain.NewRecordInfo("", "", nil)))
+	entry := domain.Must(domain.NewPoolEntry(day(14, 9), 2, domain.PoolIn))
+
+	failed := errors.New("failed")
+
+	err = tx.WithTx(ctx, func(ctx context.Context) error {
+		err := records.Save(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		// Read inside the transaction, it must not stay cached
+		_, err = records.Get(ctx, record.ID())
+		if err != nil {
+			return err
+		}
+
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("got error %v, want %v", err, failed)
+	}
+
+	_, err = records.Get(ctx, record.ID())
+	if !errors.Is(err, domain.ErrRecordNotFound) {
+		t.Fatalf("a rolled back record must not be found, got %v", err)
+	}
+
+	err = tx.WithTx(ctx, func(ctx context.Context) error {
+		err := records.Save(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		// Nested transactions are part of the outer one
+		return tx.WithTx(ctx, func(ctx context.Context) error {
+			return pool.Save(ctx, entry)
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = records.Get(ctx, record.ID())
+	if err != nil {
+		t.Errorf("committed record not found, %v", err)
+	}
+
+	balance, err := pool.GetBalance(ctx)
+	if err != nil || balance != 2 {
+		t.Errorf("got pool balance %v (%v), want 2", balance, err)
+	}
+}
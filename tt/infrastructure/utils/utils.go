@@ -2,20 +2,162 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 )
 
 var execPath string
 
+// Folders for the data files (db, log) and the config file
+var dataDir, configDir string
+
 func init() {
 	ex, err := os.Executable()
 	if err != nil {
 		panic(err)
 	}
 	execPath = filepath.Dir(ex)
+
+	dataDir, configDir = defaultDirs()
+}
+
+// TT_HOME keeps everything in one folder, otherwise the XDG base directories
+// are used
+func defaultDirs() (string, string) {
+	if home := os.Getenv("TT_HOME"); home != "" {
+		return home, home
+	}
+
+	return xdgDir("XDG_DATA_HOME", ".local/share"), xdgDir("XDG_CONFIG_HOME", ".config")
 }
 
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "tt")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return execPath
+	}
+
+	return filepath.Join(home, fallback, "tt")
+}
+
+// Uses the folder for both the data and the config files
+func SetAppDir(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	dataDir, configDir = dir, dir
+
+	return nil
+}
+
+// Path of a data file
 func GeAppPath(folder string) string {
-	return fmt.Sprintf("%s/%s", execPath, folder)
+	return filepath.Join(dataDir, folder)
+}
+
+// Path of a config file
+func GetConfigPath(file string) string {
+	return filepath.Join(configDir, file)
+}
+
+// Creates the app folders and moves there the files that older versions kept
+// next to the executable, files already on the new folders are not replaced.
+// Returns the moved files.
+func PrepareAppDirs(dataFiles, configFiles []string) ([]string, error) {
+	for _, dir := range []string{dataDir, configDir} {
+		err := os.MkdirAll(dir, 0700)
+		if err != nil {
+			return nil, fmt.Errorf("can't create %s, %w", dir, err)
+		}
+	}
+
+	moved := []string{}
+
+	for _, move := range []struct {
+		patterns []string
+		dir      string
+	}{{dataFiles, dataDir}, {configFiles, configDir}} {
+		if sameDir(execPath, move.dir) {
+			continue
+		}
+
+		for _, pattern := range move.patterns {
+			files, err := filepath.Glob(filepath.Join(execPath, pattern))
+			if err != nil {
+				return moved, err
+			}
+
+			for _, file := range files {
+				dst := filepath.Join(move.dir, filepath.Base(file))
+				if _, err := os.Stat(dst); err == nil {
+					continue
+				}
+
+				err = moveFile(file, dst)
+				if err != nil {
+					return moved, fmt.Errorf("can't move %s to %s, %w", file, dst, err)
+				}
+
+				moved = append(moved, dst)
+			}
+		}
+	}
+
+	return moved, nil
+}
+
+func sameDir(a, b string) bool {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+
+	ib, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(ia, ib)
+}
+
+// Renames the file, copying it when the folders are on different devices
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Remove(src)
 }